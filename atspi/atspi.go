@@ -0,0 +1,324 @@
+// Package atspi publishes the accessibility tree of the windows over AT-SPI,
+// the D-Bus protocol used by screen readers like Orca on Linux.
+//
+// The bridge is optional. Call Start after sys.Init, and Close before sys.Shutdown.
+package atspi
+
+import (
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/jkvatne/jkvgui/sys"
+)
+
+const (
+	basePath = "/org/a11y/atspi/accessible"
+	rootPath = dbus.ObjectPath(basePath + "/root")
+	nullPath = dbus.ObjectPath("/org/a11y/atspi/null")
+
+	ifAccessible  = "org.a11y.atspi.Accessible"
+	ifApplication = "org.a11y.atspi.Application"
+	ifComponent   = "org.a11y.atspi.Component"
+	ifText        = "org.a11y.atspi.Text"
+	ifProperties  = "org.freedesktop.DBus.Properties"
+	ifEvent       = "org.a11y.atspi.Event.Object"
+)
+
+// Ref is a reference to an accessible object, the (so) type in AT-SPI.
+type Ref struct {
+	Name string
+	Path dbus.ObjectPath
+}
+
+// Bridge is a connection to the accessibility bus, exporting the windows as AT-SPI objects.
+type Bridge struct {
+	conn    *dbus.Conn
+	name    string
+	parent  Ref
+	mutex   sync.Mutex
+	windows []*window
+	nextId  int
+	appId   int32
+}
+
+// window is the last accessibility tree received for a window
+type window struct {
+	win  *sys.Window
+	id   int
+	tree *sys.AccessTree
+}
+
+// Start connects to the accessibility bus and publishes the windows.
+// The name is the application name shown by the screen reader.
+// If the accessibility bus is not found, the session bus is used.
+func Start(name string) (*Bridge, error) {
+	session, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, err
+	}
+	var addr string
+	err = session.Object("org.a11y.Bus", "/org/a11y/bus").Call("org.a11y.Bus.GetAddress", 0).Store(&addr)
+	if err != nil {
+		slog.Info("No accessibility bus, using the session bus", "Error", err)
+		return StartOn(session, name)
+	}
+	conn, err := dbus.Connect(addr)
+	if err != nil {
+		slog.Info("Could not connect to the accessibility bus, using the session bus", "Error", err)
+		return StartOn(session, name)
+	}
+	_ = session.Close()
+	return StartOn(conn, name)
+}
+
+// StartOn publishes the windows on the given bus connection.
+// It is used by Start, and by tests running a private bus.
+func StartOn(conn *dbus.Conn, name string) (*Bridge, error) {
+	b := &Bridge{conn: conn, name: name, parent: Ref{Path: nullPath}}
+	if err := conn.ExportSubtree(accessible{b}, basePath, ifAccessible); err != nil {
+		return nil, err
+	}
+	if err := conn.ExportSubtree(component{b}, basePath, ifComponent); err != nil {
+		return nil, err
+	}
+	if err := conn.ExportSubtree(text{b}, basePath, ifText); err != nil {
+		return nil, err
+	}
+	if err := conn.ExportSubtree(properties{b}, basePath, ifProperties); err != nil {
+		return nil, err
+	}
+	// Register with the desktop. This fails when no registry is running, and
+	// screen readers can still find the application by its bus name.
+	var parent Ref
+	err := conn.Object("org.a11y.atspi.Registry", rootPath).Call(
+		"org.a11y.atspi.Socket.Embed", 0, Ref{Name: conn.Names()[0], Path: rootPath}).Store(&parent)
+	if err != nil {
+		slog.Debug("Could not embed in the accessibility registry", "Error", err)
+	} else {
+		b.parent = parent
+	}
+	sys.OnAccessTreeChange = b.update
+	return b, nil
+}
+
+// Close stops publishing the windows and closes the bus connection.
+func (b *Bridge) Close() error {
+	sys.OnAccessTreeChange = nil
+	return b.conn.Close()
+}
+
+// update is called from EndFrame when the tree of a window has changed.
+// Screen readers are notified about new windows, changed focus and changed children.
+func (b *Bridge) update(win *sys.Window, tree *sys.AccessTree) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	i := 0
+	for i < len(b.windows) && b.windows[i].win != win {
+		i++
+	}
+	if tree == nil {
+		if i < len(b.windows) {
+			b.emit(rootPath, "ChildrenChanged", "remove", int32(i), b.windowRef(b.windows[i]))
+			b.windows = append(b.windows[:i], b.windows[i+1:]...)
+		}
+		return
+	}
+	if i == len(b.windows) {
+		b.nextId++
+		w := &window{win: win, id: b.nextId, tree: tree}
+		b.windows = append(b.windows, w)
+		b.emit(rootPath, "ChildrenChanged", "add", int32(i), b.windowRef(w))
+		return
+	}
+	w := b.windows[i]
+	old := w.tree
+	w.tree = tree
+	if len(old.Root.Children) != len(tree.Root.Children) {
+		b.emit(b.path(w), "ChildrenChanged", "add", int32(-1), Ref{Path: nullPath})
+	}
+	oldFocus, newFocus := focused(old.Root), focused(tree.Root)
+	if !slices.Equal(oldFocus, newFocus) {
+		if oldFocus != nil && nodeAt(tree.Root, oldFocus) != nil {
+			b.emit(b.path(w, oldFocus...), "StateChanged", "focused", int32(0), int32(0))
+		}
+		if newFocus != nil {
+			b.emit(b.path(w, newFocus...), "StateChanged", "focused", int32(1), int32(0))
+		}
+	}
+}
+
+// emit sends an AT-SPI object event with the signature (siiva{sv})
+func (b *Bridge) emit(path dbus.ObjectPath, member string, detail string, d1 int32, value any) {
+	err := b.conn.Emit(path, ifEvent+"."+member, detail, d1, int32(0), dbus.MakeVariant(value), map[string]dbus.Variant{})
+	if err != nil {
+		slog.Debug("Could not emit AT-SPI event", "Event", member, "Error", err)
+	}
+}
+
+// focused returns the indexes leading from n to the focused node, or nil
+func focused(n *sys.AccessNode) []int {
+	for i, c := range n.Children {
+		if c.State&sys.StateFocused != 0 {
+			return []int{i}
+		}
+		if f := focused(c); f != nil {
+			return append([]int{i}, f...)
+		}
+	}
+	return nil
+}
+
+// nodeAt returns the node found by following the indexes from n, or nil
+func nodeAt(n *sys.AccessNode, indexes []int) *sys.AccessNode {
+	for _, i := range indexes {
+		if i < 0 || i >= len(n.Children) {
+			return nil
+		}
+		n = n.Children[i]
+	}
+	return n
+}
+
+// path returns the object path of the window (without indexes) or of the node
+// found by following the indexes from the root, like /w1/n3/n1
+func (b *Bridge) path(w *window, indexes ...int) dbus.ObjectPath {
+	p := basePath + "/w" + strconv.Itoa(w.id)
+	for _, i := range indexes {
+		p += "/n" + strconv.Itoa(i)
+	}
+	return dbus.ObjectPath(p)
+}
+
+func (b *Bridge) ref(path dbus.ObjectPath) Ref {
+	return Ref{Name: b.conn.Names()[0], Path: path}
+}
+
+func (b *Bridge) windowRef(w *window) Ref {
+	return b.ref(b.path(w))
+}
+
+// object is a snapshot of the AT-SPI object at a path. For the application,
+// w is nil, and for a window, node is the root of the tree and index is -1.
+// For widgets, indexes leads from the root to the node, and index is the last of them.
+type object struct {
+	w       *window
+	tree    *sys.AccessTree
+	node    *sys.AccessNode
+	indexes []int
+	index   int
+	// windowIndex is the position of the window in the application
+	windowIndex int
+}
+
+// lookup finds the object for the path of a method call
+func (b *Bridge) lookup(msg dbus.Message) (object, *dbus.Error) {
+	path, _ := msg.Headers[dbus.FieldPath].Value().(dbus.ObjectPath)
+	if path == rootPath {
+		return object{index: -1, windowIndex: -1}, nil
+	}
+	p, ok := strings.CutPrefix(string(path), basePath+"/w")
+	if !ok {
+		return object{}, noObject(path)
+	}
+	parts := strings.Split(p, "/n")
+	id, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return object{}, noObject(path)
+	}
+	indexes := make([]int, len(parts)-1)
+	for i, s := range parts[1:] {
+		if indexes[i], err = strconv.Atoi(s); err != nil {
+			return object{}, noObject(path)
+		}
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for i, w := range b.windows {
+		if w.id != id {
+			continue
+		}
+		o := object{w: w, tree: w.tree, node: nodeAt(w.tree.Root, indexes), index: -1, windowIndex: i}
+		if o.node == nil {
+			return object{}, noObject(path)
+		}
+		if len(indexes) > 0 {
+			o.indexes, o.index = indexes, indexes[len(indexes)-1]
+		}
+		return o, nil
+	}
+	return object{}, noObject(path)
+}
+
+func noObject(path dbus.ObjectPath) *dbus.Error {
+	e := dbus.MakeNoObjectError(path)
+	return &e
+}
+
+// children returns references to the children of the object
+func (b *Bridge) children(o object) []Ref {
+	var refs []Ref
+	if o.w == nil {
+		b.mutex.Lock()
+		for _, w := range b.windows {
+			refs = append(refs, b.windowRef(w))
+		}
+		b.mutex.Unlock()
+	} else {
+		for i := range o.node.Children {
+			refs = append(refs, b.ref(b.path(o.w, append(slices.Clone(o.indexes), i)...)))
+		}
+	}
+	return refs
+}
+
+// parentRef returns a reference to the object's parent
+func (b *Bridge) parentRef(o object) Ref {
+	switch {
+	case o.w == nil:
+		return b.parent
+	case o.index < 0:
+		return b.ref(rootPath)
+	}
+	return b.ref(b.path(o.w, o.indexes[:len(o.indexes)-1]...))
+}
+
+func (o object) name(b *Bridge) string {
+	if o.w == nil {
+		return b.name
+	}
+	return o.node.Name
+}
+
+// hasText is true for objects implementing the Text interface
+func (o object) hasText() bool {
+	if o.node == nil {
+		return false
+	}
+	switch o.node.Role {
+	case sys.RoleEdit, sys.RoleMemo, sys.RoleComboBox, sys.RoleLabel, sys.RoleSpinButton:
+		return true
+	}
+	return false
+}
+
+// text is the Value of editable widgets, and the Name of labels
+func (o object) text() []rune {
+	if o.node.Role == sys.RoleLabel {
+		return []rune(o.node.Name)
+	}
+	return []rune(o.node.Value)
+}
+
+// focus asks the window to give keyboard focus to the widget
+func (o object) focus() bool {
+	if o.node == nil || o.node.Tag == nil {
+		return false
+	}
+	win, tag := o.w.win, o.node.Tag
+	sys.Post(win, func() { win.SetFocusedTag(tag) })
+	return true
+}
@@ -0,0 +1,472 @@
+package atspi
+
+import (
+	"slices"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/jkvatne/jkvgui/f32"
+	"github.com/jkvatne/jkvgui/sys"
+)
+
+// AT-SPI roles, from the AtspiRole enum
+const (
+	roleInvalid     = 0
+	roleCheckBox    = 7
+	roleCheckMenu   = 8
+	roleComboBox    = 11
+	roleFrame       = 23
+	roleLabel       = 29
+	roleMenu        = 33
+	roleMenuBar     = 34
+	roleMenuItem    = 35
+	rolePageTab     = 37
+	rolePushButton  = 43
+	roleRadioButton = 44
+	roleSlider      = 51
+	roleSpinButton  = 52
+	roleText        = 61
+	roleToggle      = 62
+	roleApplication = 75
+	roleEntry       = 79
+	roleTreeItem    = 91
+)
+
+var roleNames = map[uint32]string{
+	roleInvalid:     "invalid",
+	roleCheckBox:    "check box",
+	roleCheckMenu:   "check menu item",
+	roleComboBox:    "combo box",
+	roleFrame:       "frame",
+	roleLabel:       "label",
+	roleMenu:        "menu",
+	roleMenuBar:     "menu bar",
+	roleMenuItem:    "menu item",
+	rolePageTab:     "page tab",
+	rolePushButton:  "push button",
+	roleRadioButton: "radio button",
+	roleSlider:      "slider",
+	roleSpinButton:  "spin button",
+	roleText:        "text",
+	roleToggle:      "toggle button",
+	roleApplication: "application",
+	roleEntry:       "entry",
+	roleTreeItem:    "tree item",
+}
+
+// AT-SPI states, from the AtspiStateType enum
+const (
+	stateActive     = 1
+	stateChecked    = 4
+	stateEditable   = 7
+	stateEnabled    = 8
+	stateExpandable = 9
+	stateExpanded   = 10
+	stateFocusable  = 11
+	stateFocused    = 12
+	stateMultiLine  = 17
+	stateResizable  = 21
+	stateSelectable = 22
+	stateSelected   = 23
+	stateSensitive  = 24
+	stateShowing    = 25
+	stateSingleLine = 26
+	stateVisible    = 30
+	stateInvalid    = 36
+)
+
+// Component layers
+const (
+	layerWidget = 3
+	layerWindow = 7
+)
+
+func (o object) role() uint32 {
+	if o.w == nil {
+		return roleApplication
+	}
+	switch o.node.Role {
+	case sys.RoleWindow:
+		return roleFrame
+	case sys.RoleLabel:
+		return roleLabel
+	case sys.RoleButton:
+		return rolePushButton
+	case sys.RoleCheckBox:
+		return roleCheckBox
+	case sys.RoleRadioButton:
+		return roleRadioButton
+	case sys.RoleSwitch:
+		return roleToggle
+	case sys.RoleEdit:
+		return roleEntry
+	case sys.RoleComboBox:
+		return roleComboBox
+	case sys.RoleMemo:
+		return roleText
+	case sys.RoleTreeItem:
+		return roleTreeItem
+	case sys.RoleTab:
+		return rolePageTab
+	case sys.RoleMenuBar:
+		return roleMenuBar
+	case sys.RoleMenu:
+		return roleMenu
+	case sys.RoleMenuItem:
+		return roleMenuItem
+	case sys.RoleCheckMenuItem:
+		return roleCheckMenu
+	case sys.RoleSlider:
+		return roleSlider
+	case sys.RoleSpinButton:
+		return roleSpinButton
+	}
+	return roleInvalid
+}
+
+// states returns the AT-SPI state set, as two 32-bit words
+func (o object) states() []uint32 {
+	s := []uint32{0, 0}
+	set := func(bit int) { s[bit/32] |= 1 << (bit % 32) }
+	if o.w == nil {
+		return s
+	}
+	n := o.node
+	for _, bit := range []int{stateVisible, stateShowing} {
+		set(bit)
+	}
+	if n.State&sys.StateDisabled == 0 {
+		set(stateEnabled)
+		set(stateSensitive)
+	}
+	if o.index < 0 {
+		set(stateResizable)
+		if n.State&sys.StateFocused != 0 {
+			set(stateActive)
+		}
+		return s
+	}
+	flags := []struct {
+		state sys.AccessState
+		bit   int
+	}{
+		{sys.StateFocusable, stateFocusable},
+		{sys.StateFocused, stateFocused},
+		{sys.StateChecked, stateChecked},
+		{sys.StateEditable, stateEditable},
+		{sys.StateExpandable, stateExpandable},
+		{sys.StateExpanded, stateExpanded},
+		{sys.StateSelected, stateSelected},
+		{sys.StateInvalid, stateInvalid},
+	}
+	for _, f := range flags {
+		if n.State&f.state != 0 {
+			set(f.bit)
+		}
+	}
+	if n.Role == sys.RoleTreeItem || n.Role == sys.RoleTab {
+		set(stateSelectable)
+	}
+	if n.State&sys.StateMultiLine != 0 {
+		set(stateMultiLine)
+	} else if o.hasText() && n.Role != sys.RoleLabel {
+		set(stateSingleLine)
+	}
+	return s
+}
+
+// extents returns the object's rectangle in pixels, relative to the screen (coordType 0)
+// or to the window (coordType 1 and 2).
+func (o object) extents(coordType uint32) (x, y, w, h int32) {
+	if o.w == nil {
+		return 0, 0, 0, 0
+	}
+	r := o.node.Bounds
+	x, y = int32(r.X*o.tree.ScaleX), int32(r.Y*o.tree.ScaleY)
+	if coordType == 0 {
+		x, y = x+int32(o.tree.X), y+int32(o.tree.Y)
+	}
+	return x, y, int32(r.W * o.tree.ScaleX), int32(r.H * o.tree.ScaleY)
+}
+
+// accessible implements org.a11y.atspi.Accessible
+type accessible struct{ b *Bridge }
+
+type relation struct {
+	Type    uint32
+	Targets []Ref
+}
+
+func (a accessible) GetChildAtIndex(msg dbus.Message, i int32) (Ref, *dbus.Error) {
+	o, err := a.b.lookup(msg)
+	if err != nil {
+		return Ref{}, err
+	}
+	children := a.b.children(o)
+	if i < 0 || int(i) >= len(children) {
+		return Ref{Path: nullPath}, nil
+	}
+	return children[i], nil
+}
+
+func (a accessible) GetChildren(msg dbus.Message) ([]Ref, *dbus.Error) {
+	o, err := a.b.lookup(msg)
+	if err != nil {
+		return nil, err
+	}
+	return a.b.children(o), nil
+}
+
+func (a accessible) GetIndexInParent(msg dbus.Message) (int32, *dbus.Error) {
+	o, err := a.b.lookup(msg)
+	if err != nil {
+		return 0, err
+	}
+	if o.index >= 0 {
+		return int32(o.index), nil
+	}
+	return int32(o.windowIndex), nil
+}
+
+func (a accessible) GetRelationSet(msg dbus.Message) ([]relation, *dbus.Error) {
+	_, err := a.b.lookup(msg)
+	return []relation{}, err
+}
+
+func (a accessible) GetRole(msg dbus.Message) (uint32, *dbus.Error) {
+	o, err := a.b.lookup(msg)
+	if err != nil {
+		return 0, err
+	}
+	return o.role(), nil
+}
+
+func (a accessible) GetRoleName(msg dbus.Message) (string, *dbus.Error) {
+	o, err := a.b.lookup(msg)
+	if err != nil {
+		return "", err
+	}
+	return roleNames[o.role()], nil
+}
+
+func (a accessible) GetLocalizedRoleName(msg dbus.Message) (string, *dbus.Error) {
+	return a.GetRoleName(msg)
+}
+
+func (a accessible) GetState(msg dbus.Message) ([]uint32, *dbus.Error) {
+	o, err := a.b.lookup(msg)
+	if err != nil {
+		return nil, err
+	}
+	return o.states(), nil
+}
+
+func (a accessible) GetAttributes(msg dbus.Message) (map[string]string, *dbus.Error) {
+	_, err := a.b.lookup(msg)
+	return map[string]string{"toolkit": "jkvgui"}, err
+}
+
+func (a accessible) GetApplication(msg dbus.Message) (Ref, *dbus.Error) {
+	_, err := a.b.lookup(msg)
+	return a.b.ref(rootPath), err
+}
+
+func (a accessible) GetInterfaces(msg dbus.Message) ([]string, *dbus.Error) {
+	o, err := a.b.lookup(msg)
+	if err != nil {
+		return nil, err
+	}
+	if o.w == nil {
+		return []string{ifAccessible, ifApplication}, nil
+	}
+	if o.hasText() {
+		return []string{ifAccessible, ifComponent, ifText}, nil
+	}
+	return []string{ifAccessible, ifComponent}, nil
+}
+
+// component implements org.a11y.atspi.Component
+type component struct{ b *Bridge }
+
+type rect struct {
+	X, Y, W, H int32
+}
+
+func (c component) GetExtents(msg dbus.Message, coordType uint32) (rect, *dbus.Error) {
+	o, err := c.b.lookup(msg)
+	if err != nil {
+		return rect{}, err
+	}
+	x, y, w, h := o.extents(coordType)
+	return rect{x, y, w, h}, nil
+}
+
+func (c component) GetPosition(msg dbus.Message, coordType uint32) (int32, int32, *dbus.Error) {
+	o, err := c.b.lookup(msg)
+	if err != nil {
+		return 0, 0, err
+	}
+	x, y, _, _ := o.extents(coordType)
+	return x, y, nil
+}
+
+func (c component) GetSize(msg dbus.Message) (int32, int32, *dbus.Error) {
+	o, err := c.b.lookup(msg)
+	if err != nil {
+		return 0, 0, err
+	}
+	_, _, w, h := o.extents(0)
+	return w, h, nil
+}
+
+func (c component) Contains(msg dbus.Message, x, y int32, coordType uint32) (bool, *dbus.Error) {
+	o, err := c.b.lookup(msg)
+	if err != nil {
+		return false, err
+	}
+	ox, oy, w, h := o.extents(coordType)
+	return f32.Pos{X: float32(x), Y: float32(y)}.Inside(f32.Rect{X: float32(ox), Y: float32(oy), W: float32(w), H: float32(h)}), nil
+}
+
+func (c component) GetAccessibleAtPoint(msg dbus.Message, x, y int32, coordType uint32) (Ref, *dbus.Error) {
+	o, err := c.b.lookup(msg)
+	if err != nil || o.w == nil {
+		return Ref{Path: nullPath}, err
+	}
+	p := f32.Pos{X: float32(x), Y: float32(y)}
+	for i := range o.node.Children {
+		child := o
+		child.index, child.node = i, o.node.Children[i]
+		cx, cy, w, h := child.extents(coordType)
+		if p.Inside(f32.Rect{X: float32(cx), Y: float32(cy), W: float32(w), H: float32(h)}) {
+			return c.b.ref(c.b.path(o.w, append(slices.Clone(o.indexes), i)...)), nil
+		}
+	}
+	return Ref{Path: nullPath}, nil
+}
+
+func (c component) GetLayer(msg dbus.Message) (uint32, *dbus.Error) {
+	o, err := c.b.lookup(msg)
+	if err != nil {
+		return 0, err
+	}
+	if o.index < 0 {
+		return layerWindow, nil
+	}
+	return layerWidget, nil
+}
+
+func (c component) GetMDIZOrder(msg dbus.Message) (int16, *dbus.Error) {
+	_, err := c.b.lookup(msg)
+	return -1, err
+}
+
+func (c component) GetAlpha(msg dbus.Message) (float64, *dbus.Error) {
+	_, err := c.b.lookup(msg)
+	return 1.0, err
+}
+
+func (c component) GrabFocus(msg dbus.Message) (bool, *dbus.Error) {
+	o, err := c.b.lookup(msg)
+	if err != nil {
+		return false, err
+	}
+	return o.focus(), nil
+}
+
+// text implements the reading part of org.a11y.atspi.Text. Offsets are in characters.
+type text struct{ b *Bridge }
+
+func (t text) GetText(msg dbus.Message, start, end int32) (string, *dbus.Error) {
+	o, err := t.b.lookup(msg)
+	if err != nil {
+		return "", err
+	}
+	if !o.hasText() {
+		return "", nil
+	}
+	r := o.text()
+	if end < 0 || int(end) > len(r) {
+		end = int32(len(r))
+	}
+	start = max(0, min(start, end))
+	return string(r[start:end]), nil
+}
+
+func (t text) GetCharacterAtOffset(msg dbus.Message, offset int32) (int32, *dbus.Error) {
+	o, err := t.b.lookup(msg)
+	if err != nil || !o.hasText() {
+		return 0, err
+	}
+	r := o.text()
+	if offset < 0 || int(offset) >= len(r) {
+		return 0, nil
+	}
+	return r[offset], nil
+}
+
+// properties implements org.freedesktop.DBus.Properties for all objects
+type properties struct{ b *Bridge }
+
+func (p properties) Get(msg dbus.Message, iface, name string) (dbus.Variant, *dbus.Error) {
+	all, err := p.GetAll(msg, iface)
+	if err != nil {
+		return dbus.Variant{}, err
+	}
+	v, ok := all[name]
+	if !ok {
+		return dbus.Variant{}, dbus.NewError("org.freedesktop.DBus.Error.UnknownProperty", []any{"Unknown property " + name})
+	}
+	return v, nil
+}
+
+func (p properties) GetAll(msg dbus.Message, iface string) (map[string]dbus.Variant, *dbus.Error) {
+	o, err := p.b.lookup(msg)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case iface == ifAccessible:
+		return map[string]dbus.Variant{
+			"Name":         dbus.MakeVariant(o.name(p.b)),
+			"Description":  dbus.MakeVariant(""),
+			"Parent":       dbus.MakeVariant(p.b.parentRef(o)),
+			"ChildCount":   dbus.MakeVariant(int32(len(p.b.children(o)))),
+			"Locale":       dbus.MakeVariant(""),
+			"AccessibleId": dbus.MakeVariant(""),
+		}, nil
+	case iface == ifApplication && o.w == nil:
+		p.b.mutex.Lock()
+		id := p.b.appId
+		p.b.mutex.Unlock()
+		return map[string]dbus.Variant{
+			"ToolkitName":  dbus.MakeVariant("jkvgui"),
+			"Version":      dbus.MakeVariant("1.0"),
+			"AtspiVersion": dbus.MakeVariant("2.1"),
+			"Id":           dbus.MakeVariant(id),
+		}, nil
+	case iface == ifText && o.hasText():
+		return map[string]dbus.Variant{
+			"CharacterCount": dbus.MakeVariant(int32(len(o.text()))),
+			"CaretOffset":    dbus.MakeVariant(int32(0)),
+		}, nil
+	}
+	e := dbus.MakeUnknownInterfaceError(iface)
+	return nil, &e
+}
+
+// Set is only used by the registry, to give the application its Id.
+func (p properties) Set(msg dbus.Message, iface, name string, value dbus.Variant) *dbus.Error {
+	o, err := p.b.lookup(msg)
+	if err != nil {
+		return err
+	}
+	if iface != ifApplication || name != "Id" || o.w != nil {
+		return dbus.NewError("org.freedesktop.DBus.Error.PropertyReadOnly", []any{name + " is read-only"})
+	}
+	if id, ok := value.Value().(int32); ok {
+		p.b.mutex.Lock()
+		p.b.appId = id
+		p.b.mutex.Unlock()
+	}
+	return nil
+}
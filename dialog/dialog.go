@@ -1,6 +1,7 @@
 package dialog
 
 import (
+	"sync"
 	"time"
 
 	"github.com/jkvatne/jkvgui/f32"
@@ -38,6 +39,14 @@ type winMap map[*sys.Window]*wid.Wid
 
 var Dialogs winMap
 
+// windows holds the dialogues shown in separate windows, see ShowWindow.
+// The windows are drawn by their owners, in different goroutines when threaded,
+// so Dialogs and windows are protected by mutex.
+var (
+	windows = make(winMap)
+	mutex   sync.Mutex
+)
+
 var dialogStartTime = time.Now()
 
 func YesNoDialog(heading string, text string, lbl1, lbl2 string, on1, on2 func()) wid.Wid {
@@ -55,10 +64,18 @@ func YesNoDialog(heading string, text string, lbl1, lbl2 string, on1, on2 func()
 	)
 }
 
-// Hide the current dialogue
+// Hide the current dialogue. If it is shown in a separate window, the window is closed.
 func Hide() {
-	delete(Dialogs, sys.GetCurrentWindow())
 	w := sys.GetCurrentWindow()
+	mutex.Lock()
+	_, ok := windows[w]
+	delete(windows, w)
+	delete(Dialogs, w)
+	mutex.Unlock()
+	if ok {
+		w.SetShouldClose(true)
+		return
+	}
 	if w != nil {
 		w.CurrentTag = w.PrevTag
 	}
@@ -67,14 +84,62 @@ func Hide() {
 // Show a dialogue over the current window content
 // The dialogue is drawn by the widget in the parameter dialogue
 func Show(dialogue *wid.Wid, tag interface{}, prevTag interface{}) {
+	mutex.Lock()
 	Dialogs[sys.GetCurrentWindow()] = dialogue
+	mutex.Unlock()
 	sys.GetCurrentWindow().CurrentTag = tag
 	sys.GetCurrentWindow().PrevTag = prevTag
 }
 
-// Display the current dialogue
+// ShowWindow will show a dialogue in a separate modal window, owned by the current window.
+// The size is given in dp, and the window is centered on the owner. It can be moved
+// freely, also to another monitor. The window is drawn by Display(owner).
+// As glfw windows must be created by the main thread, the window is created by the
+// next call to sys.PollEvents, and ShowWindow can be called from any goroutine.
+func ShowWindow(title string, dialogue *wid.Wid, w, h int) {
+	owner := sys.GetCurrentWindow()
+	sys.RunOnMain(func() {
+		win := sys.CreateWindow(-1, -1, w, h, title, 0, 0, sys.WindowOptions{Owner: owner, Modal: true})
+		// The window is drawn by the owner's goroutine, so the context must be released
+		win.DetachContext()
+		mutex.Lock()
+		windows[win] = dialogue
+		mutex.Unlock()
+		if owner != nil {
+			owner.Invalidate()
+		}
+	})
+}
+
+// displayWindow draws a dialogue shown in a separate window
+func displayWindow(win *sys.Window, dialogue *wid.Wid) {
+	style := &DefaultDialogueStyle
+	win.StartFrame()
+	win.Gd.SolidRect(win.ClientRectDp(), theme.Colors[style.BackgroundColor])
+	ctx := wid.NewCtx(win)
+	ctx.Rect = ctx.Rect.Inset(style.Padding, 0)
+	_ = (*dialogue)(ctx)
+	win.EndFrame()
+}
+
+// Display the current dialogue, and the dialogues shown in windows owned by win.
 func Display(win *sys.Window) {
+	mutex.Lock()
+	owned := make(winMap)
+	for w, d := range windows {
+		if w.ShouldClose() {
+			delete(windows, w)
+		} else if w.Owner() == win {
+			owned[w] = d
+		}
+	}
 	CurrentDialog := Dialogs[win]
+	mutex.Unlock()
+	for w, d := range owned {
+		displayWindow(w, d)
+		win.MakeContextCurrent()
+		win.UpdateResolution()
+	}
 	win.DialogVisible = CurrentDialog != nil
 	if !win.DialogVisible {
 		return
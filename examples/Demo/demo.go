@@ -11,6 +11,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/jkvatne/jkvgui/atspi"
 	"github.com/jkvatne/jkvgui/dialog"
 	"github.com/jkvatne/jkvgui/gpu"
 	"github.com/jkvatne/jkvgui/sys"
@@ -40,8 +41,10 @@ var (
 	ss         []wid.ScrollState
 	threaded   = flag.Bool("threaded", false, "Set to test with one go-routine pr window")
 	n          = flag.Int("n", 2, "The number of windows used")
+	a11y       = flag.Bool("atspi", false, "Publish the widgets to screen readers over AT-SPI")
 	Mutex      sync.Mutex
 	progress   float32
+	onTop      [16]bool
 )
 
 func createData() {
@@ -86,63 +89,43 @@ func DlgBtnClick() {
 	slog.Info("DlgBtnClick()")
 }
 
-func Monitor1BtnClick() {
-	slog.Info("Monitor1BtnClick()")
-	w := sys.GetCurrentContext()
-	ms := sys.GetMonitors()
-	if len(ms) > 1 {
-		x, y, _, _ := ms[0].GetWorkarea()
-		w.SetPos(x+30, y+40)
-	}
-}
-
-func Monitor2BtnClick() {
-	slog.Info("Monitor2BtnClick()")
-	w := sys.GetCurrentContext()
-	ms := sys.GetMonitors()
-	if len(ms) > 1 {
-		x, y, _, _ := ms[min(1, len(ms))].GetWorkarea()
-		w.SetPos(x+30, y+40)
-	}
-}
-
-func Maximize() {
-	w := sys.GetCurrentContext()
-	slog.Info("Maximize button handler start")
-	sys.MaximizeWindow(w)
-	slog.Info("Maximize button handler exit")
+func DlgWindowBtnClick() {
+	w := dialog.YesNoDialog("Heading", "Some text in a separate window", "Yes", "No", doYes, doNo)
+	dialog.ShowWindow("Dialogue", &w, 350, 200)
+	slog.Info("DlgWindowBtnClick()")
 }
 
-func Minimize() {
-	slog.Info("Minimize()")
-	w := sys.GetCurrentContext()
-	sys.MinimizeWindow(w)
-}
-
-func FullScreen1() {
-	slog.Info("FullScreen1()")
-	w := sys.GetCurrentContext()
-	ms := sys.GetMonitors()
-	w.SetMonitor(ms[0], 0, 0, 1024, 768, 0)
-}
-
-func FullScreen2() {
-	slog.Info("FullScreen2()")
-
-	w := sys.GetCurrentContext()
-	ms := sys.GetMonitors()
-	w.SetMonitor(ms[min(1, len(ms))], 0, 0, 1024, 768, 0)
+func ExitBtnClick() {
+	slog.Info("Exit()")
+	os.Exit(0)
 }
 
-func Restore() {
-	slog.Info("Restore()")
-	w := sys.GetCurrentContext()
-	w.SetMonitor(nil, 100, 100, int(750*1.5), int(400*1.5), 0)
+// setupWindow sets the window icon and limits, and logs changes of the window state
+func setupWindow(win *sys.Window) {
+	win.SetIcon(gpu.Home, theme.Primary.Fg())
+	win.SetSizeLimits(400, 300, 0, 0)
+	win.OnStateChange = func(from, to sys.WindowState) {
+		slog.Info("Window state changed", "Window", win.Name, "From", from, "To", to)
+	}
+	win.OnResize = func(w, h float32) {
+		slog.Info("Window resized", "Window", win.Name, "W", int(w), "H", int(h))
+	}
 }
 
-func ExitBtnClick() {
-	slog.Info("Exit()")
-	os.Exit(0)
+// confirmClose asks the user before closing the window
+func confirmClose(win *sys.Window) func() bool {
+	return func() bool {
+		// The dialogue is shown from the window's own frame
+		sys.Post(win, func() {
+			d := dialog.YesNoDialog("Close window", "Do you really want to close the window?", "Yes", "No",
+				func() {
+					dialog.Hide()
+					win.SetShouldClose(true)
+				}, dialog.Hide)
+			dialog.Show(&d, nil, nil)
+		})
+		return false
+	}
 }
 
 func DoPrimary1() {
@@ -184,23 +167,23 @@ func DoHomeBtn2() {
 	slog.Info("Home button 2 clicked")
 }
 
-func Form(no int32) wid.Wid {
-	sys.WinListMutex.RLock()
-	defer sys.WinListMutex.RUnlock()
+func Form(win *sys.Window, no int32) wid.Wid {
 	return wid.Scroller(&ss[no], &wid.DefaultScrollStyle,
-		wid.Label(sys.WindowList[no].Name, wid.H1C),
+		wid.Label(win.Name, wid.H1C),
 		wid.Label("Use TAB to move focus, and Enter or space to click button", wid.L.Font(gpu.Normal10)),
-		wid.Label(fmt.Sprintf("MousePos = %5.0f, %5.0f      FPS=%0.3f", sys.WindowList[no].MousePos().X, sys.WindowList[no].MousePos().Y, sys.WindowList[no].Fps()), nil),
+		wid.Label(fmt.Sprintf("MousePos = %5.0f, %5.0f      FPS=%0.3f      State=%s", win.MousePos().X, win.MousePos().Y, win.Fps(), win.State()), nil),
 		wid.Row(nil,
-			wid.Btn("Maximize", nil, Maximize, nil, hint3),
-			wid.Btn("Minimize", nil, Minimize, nil, hint3),
-			wid.Btn("Full screen 1", nil, FullScreen1, nil, hint3),
-			wid.Btn("Full screen 2", nil, FullScreen2, nil, hint3),
-			wid.Btn("Windowed", nil, Restore, nil, hint3),
-			wid.Btn("Monitor 1", nil, Monitor1BtnClick, nil, hint1),
-			wid.Btn("Monitor 2", nil, Monitor2BtnClick, nil, hint1)),
+			wid.Btn("Maximize", nil, win.Maximize, nil, hint3),
+			wid.Btn("Minimize", nil, win.Minimize, nil, hint3),
+			wid.Btn("Full screen 1", nil, func() { win.Fullscreen(1) }, nil, hint3),
+			wid.Btn("Full screen 2", nil, func() { win.Fullscreen(2) }, nil, hint3),
+			wid.Btn("Windowed", nil, win.Restore, nil, hint3),
+			wid.Btn("Monitor 1", nil, func() { win.MoveToMonitor(1) }, nil, hint1),
+			wid.Btn("Monitor 2", nil, func() { win.MoveToMonitor(2) }, nil, hint1),
+			wid.Checkbox("On top", &onTop[no], func() { win.SetAlwaysOnTop(onTop[no]) }, nil, hint2)),
 		wid.Row(nil,
 			wid.Btn("Show dialogue", nil, DlgBtnClick, nil, hint1),
+			wid.Btn("Dialogue window", nil, DlgWindowBtnClick, nil, hint1),
 			wid.Btn("DarkMode", nil, DarkModeBtnClick, nil, hint2),
 			wid.Btn("LightMode", nil, LightModeBtnClick, nil, hint3),
 			wid.Btn("Exit", nil, ExitBtnClick, nil, hint3),
@@ -244,21 +227,19 @@ func Form(no int32) wid.Wid {
 	)
 }
 
-func show(wno int32) {
-	if wno < sys.WindowCount.Load() {
-		sys.WindowList[wno].StartFrame()
-		wid.Show(Form(wno))
-		dialog.Display(sys.WindowList[wno])
-		sys.WindowList[wno].EndFrame()
-	}
+func show(win *sys.Window, wno int32) {
+	win.StartFrame()
+	wid.Show(Form(win, wno))
+	dialog.Display(win)
+	win.EndFrame()
 }
 
-func Thread(wno int32) {
+func Thread(win *sys.Window, wno int32) {
 	runtime.LockOSThread()
-	for !sys.WindowList[wno].Window.ShouldClose() {
+	for !win.ShouldClose() {
 		// We have to make sure only one thread at a time is using glfw.
 		Mutex.Lock()
-		show(wno)
+		show(win, wno)
 		Mutex.Unlock()
 	}
 	slog.Info("Exit", "Thread", wno)
@@ -273,16 +254,27 @@ func main() {
 	sys.MinFrameDelay = time.Second / 20
 	sys.MaxFrameDelay = time.Second / 2
 	defer sys.Shutdown()
+	if *a11y {
+		if bridge, err := atspi.Start("Demo"); err != nil {
+			slog.Error("Could not start AT-SPI bridge", "Error", err)
+		} else {
+			defer bridge.Close()
+		}
+	}
 	createData()
-	sys.CreateWindow(100, 100, 1400, 1200, "Demo 1", 2, 2.0)
+	win1 := sys.CreateWindow(100, 100, 1400, 1200, "Demo 1", 2, 2.0)
+	win1.OnCloseRequest = confirmClose(win1)
+	setupWindow(win1)
+	var win2 *sys.Window
 	if *n > 1 {
-		sys.CreateWindow(200, 200, 750, 400, "Demo 2", 1, 1.0)
+		win2 = sys.CreateWindow(200, 200, 750, 400, "Demo 2", 1, 1.0)
+		setupWindow(win2)
 	}
 	started := time.Now()
 	if *threaded {
-		go Thread(0)
-		if *n > 1 {
-			go Thread(1)
+		go Thread(win1, 0)
+		if win2 != nil {
+			go Thread(win2, 1)
 		}
 		for sys.Running() {
 			// We have to make sure only one thread at a time is using glfw.
@@ -293,9 +285,9 @@ func main() {
 		slog.Info("Exit threaded demo")
 	} else {
 		for sys.Running() {
-			show(0)
-			if *n > 1 {
-				show(1)
+			show(win1, 0)
+			if win2 != nil {
+				show(win2, 1)
 			}
 			sys.PollEvents()
 			progress = float32(time.Since(started).Seconds() / 10)
@@ -101,12 +101,12 @@ func (game *Game) HandleInput() {
 	if !p.active {
 		return
 	}
-	if sys.KeyIsDown[sys.KeySpace] && p.cooldown <= 0 && p.active {
+	if game.win.KeyDown(sys.KeySpace) && p.cooldown <= 0 && p.active {
 		game.addBullet()
 		p.cooldown = p.cooldownFrames
 	}
 	if p.fuel >= 10 {
-		if sys.KeyIsDown[sys.KeyUp] && p.active {
+		if game.win.KeyDown(sys.KeyUp) && p.active {
 			angle := f32.Radians(p.rotation)
 			p.vel.X += float32(math.Cos(float64(angle)) * 0.06)
 			p.vel.Y += float32(math.Sin(float64(angle)) * 0.06)
@@ -115,11 +115,11 @@ func (game *Game) HandleInput() {
 		}
 	}
 	if p.fuel >= 1 {
-		if sys.KeyIsDown[sys.KeyLeft] {
+		if game.win.KeyDown(sys.KeyLeft) {
 			p.rotation -= 2
 			p.fuel--
 		}
-		if sys.KeyIsDown[sys.KeyRight] {
+		if game.win.KeyDown(sys.KeyRight) {
 			p.rotation += 2
 			p.fuel--
 		}
@@ -232,9 +232,8 @@ func (game *Game) SplitAsteroid(a *Asteroid, vel f32.Pos) {
 
 // Draw the game graphics (ship, bullets and asteroids)
 func (game *Game) draw() {
-	w, h := game.win.Window.GetSize()
 	gd := game.win.Gd
-	r := f32.Rect{W: float32(w), H: float32(h)}
+	r := game.win.ClientRectDp()
 	gd.SolidRect(r, f32.Blue)
 	game.DrawShip()
 	for _, b := range game.bullets {
@@ -3,12 +3,11 @@
 package main
 
 // This file demonstrates a simple grid, trying to follow https://material.io/components/data-tables
-// It scrolls vertically and horizontally and implements highlighting of rows.
+// It scrolls vertically and horizontally, is sorted by clicking the headers, and the column borders can be dragged.
 
 import (
 	"log"
 	"log/slog"
-	"sort"
 
 	"github.com/jkvatne/jkvgui/f32"
 	"github.com/jkvatne/jkvgui/gpu"
@@ -43,16 +42,22 @@ var data = []person{
 }
 
 var (
-	FileName    = "demo.txt"
-	ss          = &wid.ScrollState{}
-	selectAll   bool
-	nameIcon    *gpu.Icon
-	addressIcon *gpu.Icon
-	ageIcon     *gpu.Icon
-	dir         bool
-	ro          *wid.EditStyle
+	FileName  = "demo.txt"
+	gs        = &wid.GridState{}
+	selectAll bool
 )
 
+// columns defines the grid. Each column has a title, a getter returning a pointer
+// to the field shown, and the kind of widget used to show it.
+var columns = []wid.GridColumn[person]{
+	{Get: func(p *person) any { return &p.Selected }, Kind: wid.CellCheckbox, Width: 24, NoSort: true},
+	{Title: "Name", Get: func(p *person) any { return &p.Name }, Kind: wid.CellLabel},
+	{Title: "Address", Get: func(p *person) any { return &p.Address }, Kind: wid.CellEdit},
+	{Title: "Age", Get: func(p *person) any { return &p.Age }, Kind: wid.CellEdit, Width: 80},
+	{Title: "Gender", Get: func(p *person) any { return &p.Status }, Kind: wid.CellCombo, Width: 120,
+		Items: []string{"Male", "Female", "Other"}},
+}
+
 // makePersons will create a list of n persons for testing
 func makePersons(n int) {
 	m := n - len(data)
@@ -65,53 +70,11 @@ func makePersons(n int) {
 }
 
 func doUpdate() {
-	slog.Info("Update button was clicked")
-}
-
-func onNameClick() {
-	if dir {
-		sort.Slice(data, func(i, j int) bool { return data[i].Name >= data[j].Name })
-		nameIcon = gpu.NavigationArrowDownward
-	} else {
-		sort.Slice(data, func(i, j int) bool { return data[i].Name < data[j].Name })
-		nameIcon = gpu.NavigationArrowUpward
-	}
-	addressIcon = gpu.NavigationUnfoldMore
-	ageIcon = gpu.NavigationUnfoldMore
-	dir = !dir
-	wid.ClearBuffers()
-}
-
-func onAddressClick() {
-	if dir {
-		sort.Slice(data, func(i, j int) bool { return data[i].Address >= data[j].Address })
-		addressIcon = gpu.NavigationArrowDownward
-	} else {
-		sort.Slice(data, func(i, j int) bool { return data[i].Address < data[j].Address })
-		addressIcon = gpu.NavigationArrowUpward
-	}
-	nameIcon = gpu.NavigationUnfoldMore
-	ageIcon = gpu.NavigationUnfoldMore
-	dir = !dir
-	wid.ClearBuffers()
-}
-
-func onAgeClick() {
-	if dir {
-		sort.Slice(data, func(i, j int) bool { return data[i].Age >= data[j].Age })
-		ageIcon = gpu.NavigationArrowDownward
-	} else {
-		sort.Slice(data, func(i, j int) bool { return data[i].Age < data[j].Age })
-		ageIcon = gpu.NavigationArrowUpward
-	}
-	nameIcon = gpu.NavigationUnfoldMore
-	addressIcon = gpu.NavigationUnfoldMore
-	dir = !dir
-	wid.ClearBuffers()
+	slog.Info("Update button was clicked", "Selected", gs.Selected())
 }
 
-// onHeaderCheckboxClick is called when the header checkbox is clicked. It will set or clear all rows.
-func onHeaderCheckboxClick() {
+// onSelectAllClick is called when the select-all checkbox is clicked. It will set or clear all rows.
+func onSelectAllClick() {
 	for i := 0; i < len(data); i++ {
 		data[i].Selected = selectAll
 	}
@@ -119,41 +82,16 @@ func onHeaderCheckboxClick() {
 
 // Form is a widget that lays out the grid. This is all that is needed.
 func Form() wid.Wid {
-	// Configure a grid with headings and several rows
-	gs1 := wid.GridStyle
-	gs2 := wid.GridStyle
-	gs2.Role = theme.SecondaryContainer
-	gs1.Role = theme.PrimaryContainer
-	var gridLines []wid.Wid
-	header := wid.Row(wid.GridStyle.R(theme.TertiaryContainer),
-		wid.Checkbox("", &selectAll, onHeaderCheckboxClick, &wid.GridCheckBox, ""),
-		wid.Btn("Name", nameIcon, onNameClick, wid.Header, ""),
-		wid.Btn("Address", addressIcon, onAddressClick, wid.Header, ""),
-		wid.Btn("Age", ageIcon, onAgeClick, wid.Header, ""),
-		wid.Btn("Gender", nil, nil, wid.Header, ""),
-	)
-
-	for i := 0; i < len(data); i++ {
-		s := &gs1
-		if i%2 == 0 {
-			s = &gs2
-		}
-		gridLines = append(gridLines,
-			wid.Row(s,
-				// One row of the grid is defined here
-				wid.Checkbox("", &data[i].Selected, nil, &wid.GridCheckBox, ""),
-				wid.Edit(&data[i].Name, "", nil, ro),
-				wid.Edit(&data[i].Address, "", nil, &wid.GridEdit),
-				wid.Edit(&data[i].Age, "", nil, &wid.GridEdit),
-				wid.Combo(&data[i].Status, []string{"Male", "Female", "Other"}, "", &wid.GridCombo),
-			))
-
-	}
+	// The grid is wider than the window, so it must be scrolled horizontally
+	gridStyle := wid.DefaultDataGrid
+	gridStyle.Scroll.ContentWidth = 1200
 	return wid.Col(nil,
 		wid.Label("Grid demo", wid.H1C),
-		wid.Edit(&FileName, "Filename", nil, wid.DefaultEdit.Size(0.15, 0.85)),
-		header,
-		wid.Scroller(ss, nil, gridLines...),
+		wid.Row(nil,
+			wid.Edit(&FileName, "Filename", nil, wid.DefaultEdit.Size(0.15, 0.85)),
+			wid.Checkbox("Select all", &selectAll, onSelectAllClick, nil, ""),
+		),
+		wid.Grid(gs, &data, columns, &gridStyle),
 		wid.Line(0, 1.0, theme.Surface),
 		wid.Row(nil,
 			wid.Flex(),
@@ -171,12 +109,6 @@ func main() {
 	makePersons(30)
 	// Full monitor (maximize) on monitor 2 (if it is present), and with userScale=2
 	w := sys.CreateWindow(0, 0, 880, 380, "Grid demo", 2, 2.0)
-	// NB: The icons are loaded in the CreateWindow routine, so we must wait until now to initialize them.
-	nameIcon = gpu.NavigationUnfoldMore
-	addressIcon = gpu.NavigationUnfoldMore
-	ageIcon = gpu.NavigationUnfoldMore
-	// Read-only fields
-	ro = wid.GridEdit.RO()
 	for sys.Running() {
 		w.StartFrame()
 		// Paint a frame around the whole window
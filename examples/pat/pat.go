@@ -19,16 +19,15 @@ var (
 )
 
 func getSize() string {
-	win.Mutex.Lock()
-	defer win.Mutex.Unlock()
 	return strconv.Itoa(len(logText) - 1)
 }
 
+// addLine can be called from any goroutine. The line is added
+// at the start of the next frame, so it will not disturb drawing.
 func addLine(s string) {
-	win.Mutex.Lock()
-	defer win.Mutex.Unlock()
-	logText = append(logText, strconv.Itoa(len(logText))+" "+s)
-	sys.Invalidate()
+	sys.Post(win, func() {
+		logText = append(logText, strconv.Itoa(len(logText))+" "+s)
+	})
 }
 
 func dummyLogGenerator() {
@@ -84,7 +83,7 @@ func main() {
 	sys.Init()
 	defer sys.Shutdown()
 	win = sys.CreateWindow(0, 0, 0, 0, "IO-Card PAT", 1, 1.5)
-	img, _ := wid.NewImage("rradi16.jpg")
+	img, _ := wid.NewImage("RRADI16.jpg")
 	Images = append(Images, img)
 	dummyLogGenerator()
 	for sys.Running() {
@@ -47,6 +47,10 @@ func (p Pos) Add(q Pos) Pos {
 	return Pos{p.X + q.X, p.Y + q.Y}
 }
 
+func (p Pos) Sub(q Pos) Pos {
+	return Pos{p.X - q.X, p.Y - q.Y}
+}
+
 func (p Pos) Mult(q Pos) Pos {
 	return Pos{p.X * q.X, p.Y * q.Y}
 }
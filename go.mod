@@ -4,6 +4,7 @@ go 1.25.0
 
 require (
 	github.com/go-gl/glfw/v3.3/glfw v0.0.0-20250301202403-da16c1255728
+	github.com/godbus/dbus/v5 v5.2.2
 	github.com/jkvatne/purego-glfw v0.2.1
 	golang.org/x/exp v0.0.0-20251023183803-a4bb9ffd2546
 	golang.org/x/image v0.38.0
@@ -9,7 +9,9 @@ import (
 	"image/png"
 	"log/slog"
 	"os"
+	"slices"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/jkvatne/jkvgui/f32"
@@ -41,6 +43,10 @@ var Fonts [32]*Font
 // DefaultDpi is the value used by the freetype library
 var DefaultDpi float32 = 72
 
+// MaxFontSets is the number of glyph sets with different dpi that are kept for each font,
+// including the one the font was loaded with, see DrawText.
+var MaxFontSets = 4
+
 // A Font allows rendering of a text.
 type Font struct {
 	FontChar     map[rune]*charInfo
@@ -57,6 +63,10 @@ type Font struct {
 	maxCharWidth int
 	Height       float32
 	Baseline     float32
+	// mutex guards the glyphs, as windows can be drawn from different goroutines
+	mutex sync.Mutex
+	// scaled has the glyphs for windows with other dpi values, least recently used first
+	scaled []*Font
 }
 
 type charInfo struct {
@@ -92,6 +102,76 @@ func LoadDefaultFonts(dpi float32) {
 	slog.Debug("LoadDefaultFonts()", "time", time.Since(t))
 }
 
+// forDpi returns the font with glyphs made for the dpi, so windows on monitors
+// with different scaling all get sharp text. The glyphs are generated when the dpi
+// is new, and the least recently used sets are freed. The mutex must be locked.
+func (f *Font) forDpi(dpi float32) *Font {
+	if dpi <= 0 || dpi == f.dpi {
+		return f
+	}
+	for i, g := range f.scaled {
+		if g.dpi == dpi {
+			f.scaled = append(append(f.scaled[:i:i], f.scaled[i+1:]...), g)
+			return g
+		}
+	}
+	t := time.Now()
+	g := f.withDpi(dpi)
+	slog.Debug("Glyphs generated", "font", f.Name, "size", f.Size, "dpi", dpi, "time", time.Since(t))
+	f.scaled = append(f.scaled, g)
+	for len(f.scaled) > max(1, MaxFontSets-1) {
+		f.scaled[0].release()
+		f.scaled = f.scaled[1:]
+	}
+	return g
+}
+
+// withDpi returns a copy of the font, with the same glyphs generated for another dpi.
+func (f *Font) withDpi(dpi float32) *Font {
+	g := &Font{FontChar: make(map[rune]*charInfo), ttf: f.ttf, Name: f.Name, Size: f.Size, dpi: dpi, Weight: f.Weight, No: f.No}
+	runes := make([]rune, 0, len(f.FontChar))
+	for r := range f.FontChar {
+		runes = append(runes, r)
+	}
+	slices.Sort(runes)
+	// Generate the glyphs in ranges of consecutive runes
+	for i := 0; i < len(runes); {
+		j := i + 1
+		for j < len(runes) && runes[j] == runes[j-1]+1 {
+			j++
+		}
+		_ = g.GenerateGlyphs(runes[i], runes[j-1])
+		i = j
+	}
+	return g
+}
+
+// release frees the glyph textures
+func (f *Font) release() {
+	if f == nil {
+		return
+	}
+	for _, ch := range f.FontChar {
+		gpu.DeleteTexture(ch.TextureID)
+	}
+}
+
+// Dpi returns the resolution the font was loaded with. It is used for measuring text.
+func (f *Font) Dpi() float32 {
+	return f.dpi
+}
+
+// Dpis returns the resolutions glyphs have been generated for, starting with Dpi().
+func (f *Font) Dpis() []float32 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	dpis := []float32{f.dpi}
+	for _, g := range f.scaled {
+		dpis = append(dpis, g.dpi)
+	}
+	return dpis
+}
+
 // Get returns the font with the given number
 func Get(no int) *Font {
 	f := Fonts[no]
@@ -121,6 +201,7 @@ func assertRune(f *Font, r rune) *charInfo {
 // DrawText draws a string to the screen, takes a list of arguments like printf
 // max is the maximum width. If longer, ellipsis is appended
 // scale is the size relative to the default text size, typically between 0.7 and 2.5.
+// The glyphs used are made for the window's scaling, given by Gd.ScaleX.
 func (f *Font) DrawText(Gd gpu.GlData, x, y float32, color f32.Color, maxW float32, dir gpu.Direction, str string) {
 	runes := []rune(str)
 	if len(runes) == 0 {
@@ -128,6 +209,9 @@ func (f *Font) DrawText(Gd gpu.GlData, x, y float32, color f32.Color, maxW float
 	}
 	gpu.GetErrors("DrawText")
 	f32.ExitIf(f == nil, "Font is nil")
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f = f.forDpi(DefaultDpi * Gd.ScaleX)
 	x *= Gd.ScaleX
 	y *= Gd.ScaleY
 	maxW *= Gd.ScaleX
@@ -175,6 +259,8 @@ func (f *Font) Width(str string) float32 {
 	if str == "" {
 		return 0
 	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
 	indices := []rune(str)
 	// Iterate through all characters in a string
 	width := 0
@@ -187,6 +273,8 @@ func (f *Font) Width(str string) float32 {
 
 // RuneNo will give the rune number at pixel position x from the start
 func (f *Font) RuneNo(x float32, s string) int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
 	runes := []rune(s)
 	width := float32(0)
 	x = x * f.dpi / DefaultDpi
@@ -213,6 +301,8 @@ func Split(str string, maxWidth float32, font *Font) []string {
 	if maxWidth == 0 || maxW > len(str)*font.maxCharWidth {
 		return []string{str}
 	}
+	font.mutex.Lock()
+	defer font.mutex.Unlock()
 	runes := []rune(str)
 	// w is the running total for the width of the current line.
 	w := 0
@@ -24,6 +24,13 @@ var (
 	NavigationArrowDropDown *Icon
 	NavigationArrowDropUp   *Icon
 	ArrowDropDown           *Icon
+	NavigationClose         *Icon
+	NavigationChevronLeft   *Icon
+	NavigationChevronRight  *Icon
+	NavigationCheck         *Icon
+	ContentCut              *Icon
+	ContentCopy             *Icon
+	ContentPaste            *Icon
 )
 
 var arrowDropDownData = []byte{
@@ -81,4 +88,37 @@ func LoadIcons() {
 	NavigationArrowUpward = New(48, icons.NavigationArrowUpward)
 	NavigationUnfoldMore = New(48, icons.NavigationUnfoldMore)
 	NavigationArrowDropUp = New(48, icons.NavigationArrowDropUp)
+	NavigationClose = New(48, icons.NavigationClose)
+	NavigationChevronLeft = New(48, icons.NavigationChevronLeft)
+	NavigationChevronRight = New(48, icons.NavigationChevronRight)
+	NavigationCheck = New(48, icons.NavigationCheck)
+	ContentCut = New(48, icons.ContentContentCut)
+	ContentCopy = New(48, icons.ContentContentCopy)
+	ContentPaste = New(48, icons.ContentContentPaste)
+}
+
+// Image returns the icon as an image with the given size and color.
+// It is used where the icon is needed outside OpenGL, like for cursors and window icons.
+// Each pixel is the average of the icon pixels it covers.
+func (icon *Icon) Image(size int, c f32.Color) *image.RGBA {
+	src := icon.img
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	h := max(1, size*sh/sw)
+	img := image.NewRGBA(image.Rect(0, 0, size, h))
+	for y := 0; y < h; y++ {
+		y1, y2 := y*sh/h, max(y*sh/h+1, (y+1)*sh/h)
+		for x := 0; x < size; x++ {
+			x1, x2 := x*sw/size, max(x*sw/size+1, (x+1)*sw/size)
+			sum, n := 0, 0
+			for sy := y1; sy < y2; sy++ {
+				for sx := x1; sx < x2; sx++ {
+					sum += int(src.Pix[sy*src.Stride+sx*4+3])
+					n++
+				}
+			}
+			a := float32(sum) / float32(n) / 255 * c.A
+			img.SetRGBA(x, y, color.RGBA{R: uint8(c.R * a * 255), G: uint8(c.G * a * 255), B: uint8(c.B * a * 255), A: uint8(a * 255)})
+		}
+	}
+	return img
 }
@@ -105,3 +105,8 @@ func GenerateTexture(rgba *image.RGBA) uint32 {
 	GetErrors("GenerateTexture")
 	return texture
 }
+
+// DeleteTexture frees a texture made by GenerateTexture
+func DeleteTexture(texture uint32) {
+	gl.DeleteTextures(1, &texture)
+}
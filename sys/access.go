@@ -0,0 +1,172 @@
+package sys
+
+import (
+	"github.com/jkvatne/jkvgui/f32"
+	"github.com/jkvatne/jkvgui/gpu"
+)
+
+// Role is the kind of widget, as reported to screen readers
+type Role int
+
+const (
+	RoleUnknown Role = iota
+	RoleWindow
+	RoleLabel
+	RoleButton
+	RoleCheckBox
+	RoleRadioButton
+	RoleSwitch
+	RoleEdit
+	RoleComboBox
+	RoleMemo
+	RoleTreeItem
+	RoleTab
+	RoleMenuBar
+	RoleMenu
+	RoleMenuItem
+	RoleCheckMenuItem
+	RoleSlider
+	RoleSpinButton
+)
+
+func (r Role) String() string {
+	switch r {
+	case RoleWindow:
+		return "Window"
+	case RoleLabel:
+		return "Label"
+	case RoleButton:
+		return "Button"
+	case RoleCheckBox:
+		return "CheckBox"
+	case RoleRadioButton:
+		return "RadioButton"
+	case RoleSwitch:
+		return "Switch"
+	case RoleEdit:
+		return "Edit"
+	case RoleComboBox:
+		return "ComboBox"
+	case RoleMemo:
+		return "Memo"
+	case RoleTreeItem:
+		return "TreeItem"
+	case RoleTab:
+		return "Tab"
+	case RoleMenuBar:
+		return "MenuBar"
+	case RoleMenu:
+		return "Menu"
+	case RoleMenuItem:
+		return "MenuItem"
+	case RoleCheckMenuItem:
+		return "CheckMenuItem"
+	case RoleSlider:
+		return "Slider"
+	case RoleSpinButton:
+		return "SpinButton"
+	}
+	return "Unknown"
+}
+
+// AccessState is a set of flags describing the state of a widget
+type AccessState uint32
+
+const (
+	StateFocusable AccessState = 1 << iota
+	StateFocused
+	StateChecked
+	StateDisabled
+	StateEditable
+	StateExpandable
+	StateExpanded
+	StateMultiLine
+	StateSelected
+	// StateInvalid is set when the text typed in an editor is not a valid value
+	StateInvalid
+)
+
+// AccessNode is one widget in the accessibility tree.
+type AccessNode struct {
+	Role Role
+	// Name is the label of the widget, or the hint text when there is no label
+	Name  string
+	Value string
+	State AccessState
+	// Bounds is the widget's rectangle in dp, relative to the window's client area
+	Bounds f32.Rect
+	// Tag is the widget's focus tag, or nil when it can not get focus
+	Tag      any
+	Children []*AccessNode
+}
+
+// AccessTree is the accessibility information for one frame of a window.
+// The root is the window itself, and the widgets are its children, in the order they were drawn.
+// The tree is not changed after it is made, so it can be used from other goroutines.
+type AccessTree struct {
+	Root *AccessNode
+	// X and Y is the position of the window's client area on the screen, in pixels.
+	X, Y int
+	// ScaleX and ScaleY converts the bounds from dp to pixels
+	ScaleX, ScaleY float32
+}
+
+// OnAccessTreeChange is called at the end of each frame where the accessibility tree of
+// the window has changed, and with a nil tree when the window is closed.
+// It is used by accessibility bridges, like the atspi package.
+var OnAccessTreeChange func(win *Window, tree *AccessTree)
+
+// AddAccessNode is called by widgets drawn in RenderChildren mode, to add themselves
+// to the accessibility tree of the frame.
+func (win *Window) AddAccessNode(n AccessNode) {
+	if n.Tag != nil && win.Focused && gpu.TagsEqual(n.Tag, win.CurrentTag) {
+		n.State |= StateFocused
+	}
+	win.accessNodes = append(win.accessNodes, &n)
+}
+
+// AccessTree returns the accessibility tree of the last completed frame.
+func (win *Window) AccessTree() *AccessTree {
+	return win.accessTree
+}
+
+// updateAccessTree is called from EndFrame. It makes the tree from the nodes added
+// in this frame, and calls OnAccessTreeChange if it differs from the previous frame.
+func (win *Window) updateAccessTree() {
+	root := &AccessNode{
+		Role:     RoleWindow,
+		Name:     win.Title(),
+		Bounds:   f32.Rect{W: win.WidthDp, H: win.HeightDp},
+		Children: win.accessNodes,
+	}
+	if win.Focused {
+		root.State = StateFocused
+	}
+	win.accessNodes = nil
+	tree := &AccessTree{Root: root, ScaleX: win.Gd.ScaleX, ScaleY: win.Gd.ScaleY}
+	if OnAccessTreeChange != nil && win.Window != nil {
+		tree.X, tree.Y = win.Window.GetPos()
+	}
+	old := win.accessTree
+	win.accessTree = tree
+	if OnAccessTreeChange != nil && (old == nil || !old.equal(tree)) {
+		OnAccessTreeChange(win, tree)
+	}
+}
+
+func (t *AccessTree) equal(u *AccessTree) bool {
+	return t.X == u.X && t.Y == u.Y && t.ScaleX == u.ScaleX && t.ScaleY == u.ScaleY && t.Root.equal(u.Root)
+}
+
+func (n *AccessNode) equal(m *AccessNode) bool {
+	if n.Role != m.Role || n.Name != m.Name || n.Value != m.Value || n.State != m.State ||
+		n.Bounds != m.Bounds || !gpu.TagsEqual(n.Tag, m.Tag) || len(n.Children) != len(m.Children) {
+		return false
+	}
+	for i := range n.Children {
+		if !n.Children[i].equal(m.Children[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,32 @@
+package sys
+
+import "log/slog"
+
+// Close asks the window to close. The window's OnCloseRequest function is called
+// first, both here and when the user clicks the close button. It can return false
+// to keep the window open, for example to ask for confirmation first.
+// Use SetShouldClose(true) to close the window without asking.
+func (win *Window) Close() {
+	if win.closeAllowed() {
+		win.SetShouldClose(true)
+	}
+}
+
+// HandleClose is called when the user clicks the window's close button.
+func (win *Window) HandleClose() {
+	win.SetShouldClose(win.closeAllowed())
+}
+
+// closeAllowed calls OnCloseRequest. A window can not be closed by the user
+// while it is blocked by a modal window.
+func (win *Window) closeAllowed() bool {
+	if m := win.ModalChild(); m != nil {
+		m.focus()
+		return false
+	}
+	if win.OnCloseRequest != nil && !win.OnCloseRequest() {
+		slog.Debug("Close cancelled", "Wno", win.Wno+1)
+		return false
+	}
+	return true
+}
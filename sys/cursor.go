@@ -0,0 +1,123 @@
+package sys
+
+import (
+	"image"
+
+	"github.com/jkvatne/jkvgui/f32"
+	"github.com/jkvatne/jkvgui/gpu"
+)
+
+// The standard cursors. Use Window.SetCursor() to select one of them in the current frame.
+// The cursors not found in glfw 3.3 (on linux) are replaced by similar ones.
+const (
+	ArrowCursor = iota
+	IBeamCursor
+	CrosshairCursor
+	HandCursor
+	HResizeCursor
+	VResizeCursor
+	ResizeNWSECursor
+	ResizeNESWCursor
+	ResizeAllCursor
+	NotAllowedCursor
+	standardCursorCount
+)
+
+// The cursor modes, see Window.SetCursorMode()
+const (
+	// CursorNormal is the visible cursor
+	CursorNormal = iota
+	// CursorHidden hides the cursor when it is over the window
+	CursorHidden
+	// CursorDisabled hides and captures the cursor. The mouse position is then
+	// unlimited, and is typically used for drag-panning canvases.
+	CursorDisabled
+)
+
+// Cursor is a custom cursor made from an image.
+// The glfw cursor is made when it is used the first time.
+type Cursor struct {
+	img    *image.RGBA
+	xHot   int
+	yHot   int
+	cursor *GlfwCursor
+}
+
+var (
+	standardCursors [standardCursorCount]*GlfwCursor
+	customCursors   []*Cursor
+)
+
+// NewCursor makes a custom cursor from the image. The hotspot xHot,yHot is the pixel
+// in the image that is the mouse position, counted from the top-left corner.
+func NewCursor(img *image.RGBA, xHot, yHot int) *Cursor {
+	return &Cursor{img: img, xHot: xHot, yHot: yHot}
+}
+
+// NewIconCursor makes a custom cursor from an icon, with the given size in pixels and color.
+func NewIconCursor(icon *gpu.Icon, size int, color f32.Color, xHot, yHot int) *Cursor {
+	return NewCursor(icon.Image(size, color), xHot, yHot)
+}
+
+// SetCursor selects one of the standard cursors for the current frame.
+// The cursor is reset to ArrowCursor at the start of each frame.
+func (win *Window) SetCursor(c int) {
+	win.Cursor = c
+	win.customCursor = nil
+}
+
+// SetCustomCursor selects a custom cursor for the current frame.
+func (win *Window) SetCustomCursor(c *Cursor) {
+	win.customCursor = c
+}
+
+// SetCursorMode sets the cursor mode to CursorNormal, CursorHidden or CursorDisabled.
+// The mode is kept until it is changed again.
+func (win *Window) SetCursorMode(mode int) {
+	if win.cursorMode == mode {
+		return
+	}
+	win.cursorMode = mode
+	if win.Window != nil {
+		setCursorMode(win.Window, mode)
+	}
+}
+
+// CursorMode returns the current cursor mode
+func (win *Window) CursorMode() int {
+	return win.cursorMode
+}
+
+// updateCursor is called from EndFrame. It will set the cursor selected in the frame,
+// and only calls glfw when it has changed.
+func (win *Window) updateCursor() {
+	var c *GlfwCursor
+	if win.customCursor != nil {
+		if win.customCursor.cursor == nil {
+			win.customCursor.cursor = createCursor(win.customCursor.img, win.customCursor.xHot, win.customCursor.yHot)
+			customCursors = append(customCursors, win.customCursor)
+		}
+		c = win.customCursor.cursor
+	} else {
+		id := max(0, min(win.Cursor, standardCursorCount-1))
+		if standardCursors[id] == nil {
+			standardCursors[id] = createStandardCursor(id)
+		}
+		c = standardCursors[id]
+	}
+	if c != win.currentCursor {
+		win.currentCursor = c
+		win.Window.SetCursor(c)
+	}
+}
+
+// releaseCursors is called when glfw terminates, as all cursors are then destroyed.
+func releaseCursors() {
+	for i := range standardCursors {
+		standardCursors[i] = nil
+	}
+	for _, c := range customCursors {
+		c.cursor = nil
+	}
+	customCursors = customCursors[:0]
+}
@@ -0,0 +1,47 @@
+package sys
+
+import (
+	"log/slog"
+
+	"github.com/jkvatne/jkvgui/f32"
+)
+
+// HandleDrop is called when files are dropped on the window, typically from a file manager.
+func (win *Window) HandleDrop(paths []string) {
+	x, y := win.cursorPos()
+	win.mousePos.X = float32(x) / win.Gd.ScaleX
+	win.mousePos.Y = float32(y) / win.Gd.ScaleY
+	win.record(RecordedEvent{Type: "drop", X: win.mousePos.X, Y: win.mousePos.Y, Paths: paths})
+	win.pushEvent(win.dropEvent(paths))
+	win.Invalidate()
+}
+
+// SimDrop simulates dropping files at the given position in the current frame.
+func (win *Window) SimDrop(x, y float32, paths []string) {
+	win.mousePos.X = x
+	win.mousePos.Y = y
+	win.events = append(win.events, win.dropEvent(paths))
+}
+
+func (win *Window) dropEvent(paths []string) Event {
+	slog.Debug("Files dropped", "Paths", paths, "X", win.mousePos.X, "Y", win.mousePos.Y)
+	win.DroppedFiles = paths
+	win.DropPos = win.mousePos
+	return Event{Kind: DropEvent, Paths: paths, Pos: win.mousePos}
+}
+
+// Dropped returns the paths of the files dropped inside r since the last frame, or nil.
+// The drop is consumed, so it is only reported once.
+func (win *Window) Dropped(r f32.Rect) []string {
+	if win.SuppressEvents {
+		return nil
+	}
+	for i := range win.events {
+		e := &win.events[i]
+		if e.Kind == DropEvent && !e.consumed && e.Pos.Inside(r) {
+			e.Consume()
+			return e.Paths
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package sys
+
+import (
+	"github.com/jkvatne/jkvgui/f32"
+)
+
+// EventKind is the type of input event stored in the event queue
+type EventKind int
+
+const (
+	KeyEvent EventKind = iota
+	CharEvent
+	MouseBtnEvent
+	MouseMoveEvent
+	ScrollEvent
+	DropEvent
+)
+
+// MaxEvents is the maximum number of events buffered between two frames.
+// When the queue is full, the oldest events are dropped.
+var MaxEvents = 1000
+
+// Event is a single input event as received from glfw.
+// Only the fields relevant for the given Kind are used.
+type Event struct {
+	Kind     EventKind
+	Key      Key
+	Scancode int
+	Action   Action
+	Mods     ModifierKey
+	// Char is the lower case character produced by the key in the keyboard layout, without
+	// modifiers, or 0 for keys like Enter and F1.
+	Char     rune
+	Rune     rune
+	Button   MouseButton
+	Pos      f32.Pos
+	Dx, Dy   float32
+	Paths    []string
+	consumed bool
+}
+
+// Typed is true for key events that should be handled as a keystroke.
+// Like LastKey, this is the key release and the auto-repeat events.
+func (e *Event) Typed() bool {
+	return e.Kind == KeyEvent && (e.Action == Release || e.Action == Repeat)
+}
+
+// Consume marks the event as handled, so it is not seen by other widgets.
+func (e *Event) Consume() {
+	e.consumed = true
+}
+
+// pushEvent is called from the glfw callbacks. The events are buffered
+// until the next call to StartFrame. Consecutive mouse moves are merged.
+func (win *Window) pushEvent(e Event) {
+	win.eventMutex.Lock()
+	defer win.eventMutex.Unlock()
+	n := len(win.pendingEvents)
+	if e.Kind == MouseMoveEvent && n > 0 && win.pendingEvents[n-1].Kind == MouseMoveEvent {
+		win.pendingEvents[n-1] = e
+		return
+	}
+	if n >= MaxEvents {
+		win.pendingEvents = append(win.pendingEvents[:0], win.pendingEvents[1:]...)
+	}
+	win.pendingEvents = append(win.pendingEvents, e)
+}
+
+// nextEvents makes the events received since the last frame available
+// to the widgets. It is called from StartFrame.
+func (win *Window) nextEvents() {
+	win.eventMutex.Lock()
+	defer win.eventMutex.Unlock()
+	win.events, win.pendingEvents = win.pendingEvents, win.events[:0]
+}
+
+// Events returns all events for the current frame that are not yet consumed.
+func (win *Window) Events() []*Event {
+	var list []*Event
+	for i := range win.events {
+		if !win.events[i].consumed {
+			list = append(list, &win.events[i])
+		}
+	}
+	return list
+}
+
+// KeyEvents returns the key and char events for the current frame that are not yet consumed,
+// in the order they were received.
+func (win *Window) KeyEvents() []*Event {
+	if win.SuppressEvents {
+		return nil
+	}
+	var list []*Event
+	for i := range win.events {
+		e := &win.events[i]
+		if !e.consumed && (e.Kind == KeyEvent || e.Kind == CharEvent) {
+			list = append(list, e)
+		}
+	}
+	return list
+}
+
+// TakeKey will consume the first typed key matching one of the given keys, and return true.
+// If no such key is found in the queue, it returns false.
+func (win *Window) TakeKey(keys ...Key) bool {
+	for _, e := range win.KeyEvents() {
+		if !e.Typed() {
+			continue
+		}
+		for _, k := range keys {
+			if e.Key == k {
+				e.Consume()
+				win.LastKey = 0
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SimKey simulates a keystroke (press and release) in the current frame.
+func (win *Window) SimKey(key Key, mods ModifierKey) {
+	win.SimKeyEvent(key, 0, Press, mods)
+	win.SimKeyEvent(key, 0, Release, mods)
+}
+
+// SimChar simulates a typed character in the current frame.
+func (win *Window) SimChar(char rune) {
+	win.events = append(win.events, Event{Kind: CharEvent, Rune: char})
+	win.LastRune = char
+}
@@ -2,10 +2,45 @@ package sys
 
 import (
 	"reflect"
+	"sort"
 
 	"github.com/jkvatne/jkvgui/gpu"
 )
 
+// Navigation groups are containers where the arrow keys move the focus
+// between the children. See PushNavGroup.
+const (
+	NavHorizontal = iota
+	NavVertical
+	NavGrid
+)
+
+type navGroup struct {
+	axis     int
+	columns  int
+	children int
+}
+
+type navLevel struct {
+	group int
+	child int
+}
+
+// focusItem is a focusable widget drawn in the current frame, in draw order.
+type focusItem struct {
+	tag      any
+	order    int
+	tabIndex int
+	trap     any
+	scopes   []any
+	path     []navLevel
+}
+
+type tabIndex struct {
+	tag   any
+	index int
+}
+
 func (win *Window) MoveByKey(forward bool) {
 	if forward {
 		win.MoveToNext = true
@@ -14,6 +49,9 @@ func (win *Window) MoveByKey(forward bool) {
 	}
 }
 
+// At returns true if the widget with the given tag has focus.
+// It must be called once pr frame by all widgets that can have focus,
+// as it also registers the widget for keyboard navigation.
 func (win *Window) At(tag interface{}) bool {
 	if win.SuppressEvents {
 		return false
@@ -21,27 +59,82 @@ func (win *Window) At(tag interface{}) bool {
 	if win.CurrentTag == nil {
 		win.CurrentTag = tag
 	}
-	if win.MoveToPrevious && gpu.TagsEqual(tag, win.CurrentTag) {
-		win.CurrentTag = win.LastTag
-		win.MoveToPrevious = false
-		win.Invalidate()
-	}
-	if gpu.TagsEqual(tag, win.CurrentTag) {
-		if win.MoveToNext {
-			win.ToNext = true
-			win.MoveToNext = false
-			win.Invalidate()
-		}
-	} else if win.ToNext {
-		win.ToNext = false
-		win.CurrentTag = tag
-		win.Invalidate()
-	}
+	win.register(tag)
 	win.LastTag = tag
 	if !win.Focused {
 		return false
 	}
-	return gpu.TagsEqual(tag, win.CurrentTag) && !reflect.ValueOf(tag).IsNil()
+	focused := gpu.TagsEqual(tag, win.CurrentTag) && !reflect.ValueOf(tag).IsNil()
+	if focused {
+		win.nextFocusScopes = append(win.nextFocusScopes[:0], win.scopes...)
+	}
+	return focused
+}
+
+// register adds the tag to the focus chain for this frame
+func (win *Window) register(tag any) {
+	if tag == nil || reflect.ValueOf(tag).IsNil() {
+		return
+	}
+	n := len(win.focusChain)
+	if n > 0 && gpu.TagsEqual(win.focusChain[n-1].tag, tag) {
+		return
+	}
+	item := focusItem{tag: tag, order: n, tabIndex: win.TabIndex(tag)}
+	if len(win.traps) > 0 {
+		item.trap = win.traps[len(win.traps)-1]
+	}
+	if len(win.scopes) > 0 {
+		item.scopes = append([]any(nil), win.scopes...)
+	}
+	if len(win.navPath) > 0 {
+		item.path = append([]navLevel(nil), win.navPath...)
+	}
+	win.focusChain = append(win.focusChain, item)
+}
+
+// PushScope marks the start of a group of widgets, like a form or a panel.
+// It must be matched by a call to PopScope after the widgets are drawn.
+// Shortcuts bound to the scope tag are active when the focused widget is inside the group.
+func (win *Window) PushScope(tag any) {
+	win.scopes = append(win.scopes, tag)
+}
+
+// PopScope marks the end of the group started by PushScope
+func (win *Window) PopScope() {
+	if len(win.scopes) > 0 {
+		win.scopes = win.scopes[:len(win.scopes)-1]
+	}
+}
+
+// PushFocusScope is like PushScope, but Tab and the arrow keys will not move
+// the focus into or out of the scope. It must be matched by PopFocusScope.
+func (win *Window) PushFocusScope(tag any) {
+	win.PushScope(tag)
+	win.traps = append(win.traps, tag)
+}
+
+// PopFocusScope marks the end of the scope started by PushFocusScope
+func (win *Window) PopFocusScope() {
+	win.PopScope()
+	if len(win.traps) > 0 {
+		win.traps = win.traps[:len(win.traps)-1]
+	}
+}
+
+// FocusInScope is true if the focused widget was drawn inside the scope with the given tag
+// in the last frame.
+func (win *Window) FocusInScope(tag any) bool {
+	return inScopes(win.focusScopes, tag)
+}
+
+func inScopes(scopes []any, tag any) bool {
+	for _, s := range scopes {
+		if gpu.TagsEqual(s, tag) {
+			return true
+		}
+	}
+	return false
 }
 
 func (win *Window) SetFocusedTag(action interface{}) {
@@ -50,3 +143,286 @@ func (win *Window) SetFocusedTag(action interface{}) {
 		win.Invalidate()
 	}
 }
+
+// RequestFocus moves the focus to the widget with the given tag.
+func (win *Window) RequestFocus(tag any) {
+	win.SetFocusedTag(tag)
+}
+
+// RequestFocusIn moves the focus to the first widget in the scope with the given tag,
+// at the end of the current frame. Tab indexes are used, and skipped widgets are ignored.
+func (win *Window) RequestFocusIn(scope any) {
+	win.focusRequest = scope
+	win.Invalidate()
+}
+
+// SetTabIndex sets the tab order for the widget with the given tag.
+// Widgets with positive index come first, in increasing order, then the widgets
+// with index 0 in the order they are drawn. A negative index removes the widget
+// from Tab and arrow-key navigation, but it can still get focus by clicking it.
+func (win *Window) SetTabIndex(tag any, index int) {
+	for i := range win.tabIndexes {
+		if gpu.TagsEqual(win.tabIndexes[i].tag, tag) {
+			win.tabIndexes[i].index = index
+			return
+		}
+	}
+	win.tabIndexes = append(win.tabIndexes, tabIndex{tag: tag, index: index})
+}
+
+// TabIndex returns the tab index for the tag, or 0 if it is not set.
+func (win *Window) TabIndex(tag any) int {
+	for _, t := range win.tabIndexes {
+		if gpu.TagsEqual(t.tag, tag) {
+			return t.index
+		}
+	}
+	return 0
+}
+
+// FocusEntered is true in the first frame after the widget with the given tag got focus.
+func (win *Window) FocusEntered(tag any) bool {
+	return win.focusChanged && gpu.TagsEqual(tag, win.CurrentTag)
+}
+
+// FocusLeft is true in the first frame after the widget with the given tag lost focus.
+func (win *Window) FocusLeft(tag any) bool {
+	return win.focusChanged && gpu.TagsEqual(tag, win.previousFocus) && !gpu.TagsEqual(tag, win.CurrentTag)
+}
+
+// ScopeEntered is true in the first frame after the focus moved into the scope.
+func (win *Window) ScopeEntered(scope any) bool {
+	return inScopes(win.focusScopes, scope) && !inScopes(win.prevFocusScopes, scope)
+}
+
+// ScopeLeft is true in the first frame after the focus moved out of the scope.
+func (win *Window) ScopeLeft(scope any) bool {
+	return !inScopes(win.focusScopes, scope) && inScopes(win.prevFocusScopes, scope)
+}
+
+// PushNavGroup is called by containers before drawing their children. The arrow keys
+// will then move the focus between the children, left/right for NavHorizontal,
+// up/down for NavVertical, and both for NavGrid with the given number of columns.
+// Call NavChild(i) before drawing child number i, and PopNavGroup when done.
+func (win *Window) PushNavGroup(axis int, columns int) {
+	win.navGroups = append(win.navGroups, navGroup{axis: axis, columns: max(1, columns)})
+	win.navPath = append(win.navPath, navLevel{group: len(win.navGroups) - 1, child: -1})
+}
+
+// NavChild sets the index of the child drawn next in the current navigation group.
+func (win *Window) NavChild(i int) {
+	if n := len(win.navPath); n > 0 {
+		win.navPath[n-1].child = i
+		g := &win.navGroups[win.navPath[n-1].group]
+		g.children = max(g.children, i+1)
+	}
+}
+
+// PopNavGroup ends the navigation group started by PushNavGroup
+func (win *Window) PopNavGroup() {
+	if n := len(win.navPath); n > 0 {
+		win.navPath = win.navPath[:n-1]
+	}
+}
+
+// startFocus is called from StartFrame. It detects focus changes and clears the focus chain.
+func (win *Window) startFocus() {
+	win.focusChanged = !gpu.TagsEqual(win.CurrentTag, win.lastFocus)
+	if win.focusChanged {
+		win.previousFocus = win.lastFocus
+		win.lastFocus = win.CurrentTag
+		if win.OnFocusChange != nil {
+			win.OnFocusChange(win.previousFocus, win.CurrentTag)
+		}
+	}
+	win.prevFocusScopes = append(win.prevFocusScopes[:0], win.focusScopes...)
+	win.focusScopes, win.nextFocusScopes = win.nextFocusScopes, win.focusScopes[:0]
+	win.scopes = win.scopes[:0]
+	win.traps = win.traps[:0]
+	win.focusChain = win.focusChain[:0]
+	win.navGroups = win.navGroups[:0]
+	win.navPath = win.navPath[:0]
+}
+
+// navigate is called from EndFrame, when all focusable widgets are drawn.
+// It handles Tab, Shift+Tab and the arrow keys not used by the widgets.
+func (win *Window) navigate() {
+	cur := win.focusedItem()
+	var target *focusItem
+	if win.focusRequest != nil {
+		for i := range win.focusChain {
+			if inScopes(win.focusChain[i].scopes, win.focusRequest) && win.focusChain[i].tabIndex >= 0 {
+				target = win.tabOrder(&win.focusChain[i])[0]
+				break
+			}
+		}
+		win.focusRequest = nil
+	} else if win.MoveToNext || win.ToNext || win.MoveToPrevious {
+		target = win.tabTarget(cur, win.MoveToNext || win.ToNext)
+		win.MoveToNext, win.ToNext, win.MoveToPrevious = false, false, false
+	} else if t, ok := win.ctrlTabTarget(cur); ok {
+		target = t
+	} else if cur != nil {
+		for _, e := range win.KeyEvents() {
+			if e.Typed() && e.Mods&modMask == 0 && (e.Key == KeyUp || e.Key == KeyDown || e.Key == KeyLeft || e.Key == KeyRight) {
+				if t := win.arrowTarget(cur, e.Key); t != nil {
+					e.Consume()
+					target = t
+					break
+				}
+			}
+		}
+	}
+	if target != nil && !gpu.TagsEqual(target.tag, win.CurrentTag) {
+		win.CurrentTag = target.tag
+		win.Invalidate()
+	}
+}
+
+func (win *Window) focusedItem() *focusItem {
+	for i := range win.focusChain {
+		if gpu.TagsEqual(win.focusChain[i].tag, win.CurrentTag) {
+			return &win.focusChain[i]
+		}
+	}
+	return nil
+}
+
+// tabOrder returns the widgets reachable by Tab from the given item, sorted by tab index.
+func (win *Window) tabOrder(from *focusItem) []*focusItem {
+	var list []*focusItem
+	for i := range win.focusChain {
+		f := &win.focusChain[i]
+		if f.tabIndex >= 0 && gpu.TagsEqual(f.trap, from.trap) {
+			list = append(list, f)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i].tabIndex, list[j].tabIndex
+		return a > 0 && (b == 0 || a < b)
+	})
+	if len(list) == 0 {
+		list = append(list, from)
+	}
+	return list
+}
+
+// ctrlTabTarget returns the widget to focus for Ctrl+Tab, when it was not used by any widget.
+func (win *Window) ctrlTabTarget(cur *focusItem) (*focusItem, bool) {
+	for _, e := range win.KeyEvents() {
+		if e.Key == KeyTab && e.Action == Release && e.Mods&ModControl != 0 {
+			e.Consume()
+			return win.tabTarget(cur, e.Mods&ModShift == 0), true
+		}
+	}
+	return nil, false
+}
+
+func (win *Window) tabTarget(cur *focusItem, forward bool) *focusItem {
+	if len(win.focusChain) == 0 {
+		return nil
+	}
+	if cur == nil {
+		return win.tabOrder(&win.focusChain[0])[0]
+	}
+	list := win.tabOrder(cur)
+	n := -1
+	for i, f := range list {
+		if f == cur {
+			n = i
+		}
+	}
+	if n < 0 {
+		// The focused widget is skipped, use the nearest widget in draw order
+		var prev *focusItem
+		for _, f := range list {
+			if forward && f.order > cur.order {
+				return f
+			} else if !forward && f.order < cur.order {
+				prev = f
+			}
+		}
+		if prev != nil {
+			return prev
+		} else if forward {
+			return list[0]
+		}
+		return list[len(list)-1]
+	}
+	step := 1
+	if !forward {
+		step = len(list) - 1
+	}
+	for i := 1; i < len(list); i++ {
+		t := list[(n+i*step)%len(list)]
+		if !gpu.TagsEqual(t.tag, cur.tag) {
+			return t
+		}
+	}
+	return nil
+}
+
+// step returns the change in child index when the key is pressed in the group.
+func (g *navGroup) step(child int, key Key) int {
+	switch {
+	case key == KeyLeft && g.axis != NavVertical && (g.axis == NavHorizontal || child%g.columns > 0):
+		return -1
+	case key == KeyRight && g.axis != NavVertical && (g.axis == NavHorizontal || child%g.columns < g.columns-1):
+		return 1
+	case key == KeyUp && g.axis == NavVertical:
+		return -1
+	case key == KeyDown && g.axis == NavVertical:
+		return 1
+	case key == KeyUp && g.axis == NavGrid:
+		return -g.columns
+	case key == KeyDown && g.axis == NavGrid:
+		return g.columns
+	}
+	return 0
+}
+
+// arrowTarget finds the widget to focus when an arrow key is pressed. The innermost
+// group that can move in the given direction is used. Inside the new child, the widget
+// at the same position as the current one is preferred, so a Col of Rows works like a grid.
+func (win *Window) arrowTarget(cur *focusItem, key Key) *focusItem {
+	for d := len(cur.path) - 1; d >= 0; d-- {
+		level := cur.path[d]
+		if level.child < 0 {
+			continue
+		}
+		g := &win.navGroups[level.group]
+		step := g.step(level.child, key)
+		if step == 0 {
+			continue
+		}
+		for c := level.child + step; c >= 0 && c < g.children; c += step {
+			if g.axis == NavGrid && step*step == 1 && c/g.columns != level.child/g.columns {
+				break
+			}
+			if t := win.bestIn(cur, d, c); t != nil {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+func (win *Window) bestIn(cur *focusItem, d int, child int) *focusItem {
+	var best *focusItem
+	bestScore := -1
+	for i := range win.focusChain {
+		f := &win.focusChain[i]
+		if len(f.path) <= d || f.path[d] != (navLevel{group: cur.path[d].group, child: child}) ||
+			f.tabIndex < 0 || !gpu.TagsEqual(f.trap, cur.trap) || gpu.TagsEqual(f.tag, cur.tag) {
+			continue
+		}
+		score := 0
+		for j := d + 1; j < len(f.path) && j < len(cur.path) && f.path[j].child == cur.path[j].child; j++ {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = f, score
+		}
+	}
+	return best
+}
@@ -5,10 +5,11 @@ import (
 
 	"github.com/jkvatne/jkvgui/gpu"
 	"github.com/jkvatne/jkvgui/theme"
+	"github.com/jkvatne/purego-glfw/gl"
 )
 
 func (win *Window) StartFrame() {
-	if win.Window.ShouldClose() {
+	if win.ShouldClose() {
 		return
 	}
 	win.redraws++
@@ -26,34 +27,46 @@ func (win *Window) StartFrame() {
 	win.UpdateResolution()
 	gpu.SetBackgroundColor(theme.Canvas.Bg())
 	win.Blinking.Store(false)
-	win.Cursor = ArrowCursor
+	win.SetCursor(ArrowCursor)
+	win.frames++
+	win.runPosted()
+	win.nextEvents()
+	win.replay()
+	if win.Blocked() {
+		win.SuppressEvents = true
+	}
+	win.startFocus()
 }
 
 // EndFrame will do buffer swapping and focus updates
 func (win *Window) EndFrame() {
-	if win.Window.ShouldClose() {
+	if win.ShouldClose() {
 		return
 	}
 	if !win.DialogVisible {
-		win.SuppressEvents = false
+		win.SuppressEvents = win.Blocked()
 	}
 	win.RunDeferred()
+	win.navigate()
+	win.runUnusedKeyHandlers()
+	win.handleShortcuts()
+	win.pruneGestures()
+	win.updateAccessTree()
+	if win.Blinking.Load() {
+		startBlinking()
+	}
 	win.LastKey = 0
+	win.events = win.events[:0]
 	win.LeftBtnClicked = false
+	win.RightBtnClicked = false
+	win.MiddleBtnClicked = false
+	if win.offscreen != nil {
+		win.offscreen.resolve()
+		gl.Flush()
+		win.DetachContext()
+		return
+	}
 	win.Window.SwapBuffers()
-	switch win.Cursor {
-	case VResizeCursor:
-		win.Window.SetCursor(pVResizeCursor)
-	case HResizeCursor:
-		win.Window.SetCursor(pHResizeCursor)
-	case CrosshairCursor:
-		win.Window.SetCursor(pCrosshairCursor)
-	case HandCursor:
-		win.Window.SetCursor(pHandCursor)
-	case IBeamCursor:
-		win.Window.SetCursor(pIBeamCursor)
-	default:
-		win.Window.SetCursor(pArrowCursor)
-	}
-	DetachCurrentContext()
+	win.updateCursor()
+	win.DetachContext()
 }
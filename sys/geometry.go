@@ -0,0 +1,151 @@
+package sys
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Geometry is the saved state of a window.
+// X and Y are relative to the work area of the monitor, and all sizes are in pixels.
+type Geometry struct {
+	X, Y      int
+	W, H      int
+	Maximized bool
+	// Monitor is 1 for the primary monitor, 2 for the secondary etc.
+	Monitor   int
+	UserScale float32
+}
+
+var (
+	geometryFile  string
+	geometryMutex sync.Mutex
+)
+
+// PersistGeometry enables saving of the window geometry when windows are closed,
+// and restoring it when a window with the same name is created.
+// The geometry is stored in windows.json in a directory named appName
+// under the user's configuration directory ($XDG_CONFIG_HOME or ~/.config on Linux).
+// It should be called after Init() and before CreateWindow().
+// An empty appName will disable the persistence.
+func PersistGeometry(appName string) error {
+	if appName == "" {
+		geometryFile = ""
+		return nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return err
+	}
+	geometryFile = filepath.Join(dir, appName, "windows.json")
+	return nil
+}
+
+// GeometryFile returns the name of the file used to store window geometry, or "" if not enabled.
+func GeometryFile() string {
+	return geometryFile
+}
+
+func loadGeometries() map[string]Geometry {
+	g := make(map[string]Geometry)
+	data, err := os.ReadFile(geometryFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return g
+	} else if err != nil {
+		slog.Error("Could not read window geometry", "File", geometryFile, "Error", err)
+		return g
+	}
+	if err = json.Unmarshal(data, &g); err != nil {
+		slog.Error("Could not decode window geometry", "File", geometryFile, "Error", err)
+	}
+	return g
+}
+
+// LoadGeometry returns the saved geometry for the window with the given name.
+func LoadGeometry(name string) (Geometry, bool) {
+	if geometryFile == "" {
+		return Geometry{}, false
+	}
+	geometryMutex.Lock()
+	defer geometryMutex.Unlock()
+	g, ok := loadGeometries()[name]
+	return g, ok
+}
+
+// SaveGeometry will store the window's position, size, monitor and zoom.
+// It is called automatically when the window is closed, if PersistGeometry has been called.
+func (win *Window) SaveGeometry() error {
+	if geometryFile == "" || win.Window == nil {
+		return nil
+	}
+	geometryMutex.Lock()
+	defer geometryMutex.Unlock()
+	all := loadGeometries()
+	g := all[win.Name]
+	g.Maximized = isMaximized(win.Window)
+	g.UserScale = win.UserScale
+	x, y := win.Window.GetPos()
+	w, h := win.Window.GetSize()
+	g.Monitor = monitorAt(x+w/2, y+h/2)
+	if !g.Maximized {
+		// When maximized, the size before maximizing is kept
+		mx, my, _, _ := GetMonitors()[g.Monitor-1].GetWorkarea()
+		g.X, g.Y, g.W, g.H = x-mx, y-my, w, h
+	}
+	all[win.Name] = g
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(geometryFile), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(geometryFile, data, 0o644)
+}
+
+func (win *Window) saveGeometry() {
+	if err := win.SaveGeometry(); err != nil {
+		slog.Error("Could not save window geometry", "File", geometryFile, "Error", err)
+	}
+}
+
+// monitorAt returns the monitor number (1..n) containing the pixel position x,y,
+// or 1 if it is outside all monitors.
+func monitorAt(x, y int) int {
+	for i, m := range GetMonitors() {
+		mx, my, mw, mh := m.GetWorkarea()
+		if x >= mx && x < mx+mw && y >= my && y < my+mh {
+			return i + 1
+		}
+	}
+	return 1
+}
+
+// restoreGeometry moves and resizes the window to the saved geometry.
+// The geometry is clamped to the monitor's work area, and if the monitor
+// no longer exists, the last available monitor is used.
+// A window that has only been saved while maximized has no size, and keeps
+// the position and size given when it was created.
+func (win *Window) restoreGeometry(g Geometry) {
+	if g.W > 0 && g.H > 0 {
+		monitors := GetMonitors()
+		m := monitors[max(0, min(g.Monitor-1, len(monitors)-1))]
+		mx, my, mw, mh := m.GetWorkarea()
+		lb, tb, rb, bb := win.Window.GetFrameSize()
+		w := max(1, min(g.W, mw-lb-rb))
+		h := max(1, min(g.H, mh-tb-bb))
+		x := max(lb, min(g.X, mw-w-rb))
+		y := max(tb, min(g.Y, mh-h-bb))
+		slog.Debug("Restore geometry", "Name", win.Name, "X", x, "Y", y, "W", w, "H", h, "Monitor", g.Monitor)
+		win.Window.SetPos(mx+x, my+y)
+		win.Window.SetSize(w, h)
+	}
+	if g.UserScale > 0 {
+		win.UserScale = g.UserScale
+	}
+	w, h := win.Window.GetSize()
+	win.UpdateSize(w, h)
+}
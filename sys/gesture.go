@@ -0,0 +1,100 @@
+package sys
+
+import (
+	"github.com/jkvatne/jkvgui/f32"
+	"github.com/jkvatne/jkvgui/gpu"
+)
+
+// Gesture is the result of Window.Gesture, for one widget in the current frame.
+type Gesture struct {
+	// Hovered is true while the mouse pointer is inside the rectangle.
+	Hovered bool
+	// HoverEnter is true in the first frame the mouse pointer is inside the rectangle.
+	HoverEnter bool
+	// HoverLeave is true in the first frame the mouse pointer is outside the rectangle.
+	HoverLeave bool
+	// LongPress is true once, when the left button has been held down for LongPressTime
+	// inside the rectangle without moving the mouse.
+	LongPress bool
+	// DragStart is true in the frame where the left button was pressed inside the rectangle.
+	DragStart bool
+	// Dragging is true from DragStart until the left button is released,
+	// also when the mouse pointer is moved outside the rectangle.
+	Dragging bool
+	// DragEnd is true in the first frame after the left button was released.
+	DragEnd bool
+	// Delta is the mouse movement since the previous frame, while dragging.
+	Delta f32.Pos
+	// Offset is the mouse movement since DragStart.
+	Offset f32.Pos
+}
+
+// gestureState is the state stored between frames for each tag given to Gesture.
+type gestureState struct {
+	tag      any
+	hovered  bool
+	dragging bool
+	startPos f32.Pos
+	lastPos  f32.Pos
+	frame    int
+}
+
+// Gesture detects hover, long-press and dragging with the left button for the widget
+// with the given tag, drawn inside the rectangle r. It should be called once pr frame
+// for each widget. Only one widget is dragged at a time, and while it is dragged,
+// the other widgets are not hovered.
+func (win *Window) Gesture(r f32.Rect, tag any) Gesture {
+	s := win.gestureState(tag)
+	s.frame = win.frames
+	var g Gesture
+	if s.dragging && !gpu.TagsEqual(win.dragTag, tag) {
+		// The button was released, or the mouse buttons were cleared
+		s.dragging = false
+		g.DragEnd = true
+		g.Offset = win.mousePos.Sub(s.startPos)
+	} else if s.dragging {
+		g.Dragging = true
+		g.Delta = win.mousePos.Sub(s.lastPos)
+		g.Offset = win.mousePos.Sub(s.startPos)
+		s.lastPos = win.mousePos
+	} else if win.dragTag == nil && win.LeftBtnPressed(r) && win.btnDownPos[MouseButtonLeft].Inside(r) {
+		s.dragging = true
+		s.startPos = win.StartDrag()
+		s.lastPos = s.startPos
+		win.dragTag = tag
+		g.DragStart = true
+		g.Dragging = true
+	}
+	g.Hovered = !win.SuppressEvents && win.mousePos.Inside(r) && (!win.Dragging || s.dragging)
+	g.HoverEnter = g.Hovered && !s.hovered
+	g.HoverLeave = !g.Hovered && s.hovered
+	s.hovered = g.Hovered
+	g.LongPress = win.LeftBtnLongPress(r)
+	return g
+}
+
+// gestureState returns the state for the tag, creating a new one if not found.
+func (win *Window) gestureState(tag any) *gestureState {
+	for _, s := range win.gestures {
+		if gpu.TagsEqual(s.tag, tag) {
+			return s
+		}
+	}
+	s := &gestureState{tag: tag}
+	win.gestures = append(win.gestures, s)
+	return s
+}
+
+// pruneGestures is called from EndFrame. It removes the state of widgets
+// that were not drawn in this frame, unless they are being dragged.
+func (win *Window) pruneGestures() {
+	n := 0
+	for _, s := range win.gestures {
+		if s.frame == win.frames || s.dragging {
+			win.gestures[n] = s
+			n++
+		}
+	}
+	clear(win.gestures[n:])
+	win.gestures = win.gestures[:n]
+}
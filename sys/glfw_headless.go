@@ -0,0 +1,206 @@
+//go:build headless
+
+// This file replaces glfw_linux.go and glfw_windows.go when building with the headless tag.
+// glfw is not used at all, so no cgo, X11 or Wayland libraries are needed for glfw,
+// and the application always runs in headless mode. The key and button values are the
+// same as in glfw, so recordings can be replayed in both builds.
+// Build with "-tags headless,egl" to load the OpenGL functions with EGL instead of GLX.
+
+package sys
+
+import (
+	"errors"
+	"image"
+)
+
+var (
+	Monitors []*GlfwMonitor
+)
+
+type (
+	// GlfwWindow is never created in headless builds. Window.Window is always nil.
+	GlfwWindow struct{}
+	// GlfwCursor is never created in headless builds.
+	GlfwCursor struct{}
+	// GlfwMonitor is never created in headless builds. There are no monitors.
+	GlfwMonitor struct{}
+	Key         int
+	Action      int
+	ModifierKey int
+	MouseButton int
+)
+
+// VidMode is the video mode of a monitor
+type VidMode struct {
+	Width, Height, RedBits, GreenBits, BlueBits, RefreshRate int
+}
+
+//goland:noinspection ALL,GoUnusedConst
+const (
+	KeyRight          Key         = 262
+	KeyLeft           Key         = 263
+	KeyUp             Key         = 265
+	KeyDown           Key         = 264
+	KeyTab            Key         = 258
+	KeySpace          Key         = 32
+	KeyEnter          Key         = 257
+	KeyKPEnter        Key         = 335
+	KeyEscape         Key         = 256
+	KeyBackspace      Key         = 259
+	KeyDelete         Key         = 261
+	KeyHome           Key         = 268
+	KeyEnd            Key         = 269
+	KeyPageUp         Key         = 266
+	KeyPageDown       Key         = 267
+	KeyInsert         Key         = 260
+	KeyC              Key         = 67
+	KeyV              Key         = 86
+	KeyX              Key         = 88
+	KeyA              Key         = 65
+	Key0              Key         = 48
+	KeyF1             Key         = 290
+	KeyF10            Key         = 299
+	KeyF12            Key         = 301
+	KeyLeftShift      Key         = 340
+	KeyRightShift     Key         = 344
+	KeyLeftControl    Key         = 341
+	KeyRightControl   Key         = 345
+	KeyLeftAlt        Key         = 342
+	KeyRightAlt       Key         = 346
+	KeyLeftSuper      Key         = 343
+	KeyRightSuper     Key         = 347
+	ModShift          ModifierKey = 1
+	ModControl        ModifierKey = 2
+	ModAlt            ModifierKey = 4
+	ModSuper          ModifierKey = 8
+	Release           Action      = 0
+	Press             Action      = 1
+	Repeat            Action      = 2
+	MouseButtonLeft   MouseButton = 0
+	MouseButtonRight  MouseButton = 1
+	MouseButtonMiddle MouseButton = 2
+)
+
+func init() {
+	Headless = true
+}
+
+func WaitEventsTimeout(secondsDelay float32) {}
+
+func Terminate() {}
+
+func GetMonitors() []*GlfwMonitor {
+	return nil
+}
+
+func GetWindow(w *GlfwWindow) *Window {
+	return nil
+}
+
+func SetDefaultHints() {}
+
+func SetMaximizedHint(maximized bool) {}
+
+func createInvisibleWindow(w, h int, title string, monitor *GlfwMonitor, share *GlfwWindow) *GlfwWindow {
+	panic("glfw windows can not be created in headless builds")
+}
+
+func SetClipboardString(s string) {
+	clipboard = s
+}
+
+func GetClipboardString() (string, error) {
+	return clipboard, nil
+}
+
+func isMaximized(w *GlfwWindow) bool {
+	return false
+}
+
+// Deprecated: Use Window.Maximize
+func MaximizeWindow(w *GlfwWindow) {}
+
+// Deprecated: Use Window.Minimize
+func MinimizeWindow(w *GlfwWindow) {}
+
+func isIconified(w *GlfwWindow) bool {
+	return false
+}
+
+func isFloating(w *GlfwWindow) bool {
+	return false
+}
+
+func setFloating(w *GlfwWindow, on bool) {}
+
+func setTitle(w *GlfwWindow, title string) {}
+
+func setSizeLimits(w *GlfwWindow, minW, minH, maxW, maxH int) {}
+
+func setAspectRatio(w *GlfwWindow, numer, denom int) {}
+
+func setFullscreen(w *GlfwWindow, m *GlfwMonitor) {}
+
+func setWindowed(w *GlfwWindow, x, y, width, height int) {}
+
+func PostEmptyEvent() {}
+
+func glfwInit() error {
+	return errors.New("glfw is not available in headless builds")
+}
+
+func getKeyName(key Key, scancode int) string {
+	return ""
+}
+
+func pollMonitors() {}
+
+func DetachCurrentContext() {}
+
+func SwapInterval(n int) {}
+
+func GetCurrentContext() *GlfwWindow {
+	return nil
+}
+
+func setCallbacks(Window *GlfwWindow) {}
+
+func createStandardCursor(id int) *GlfwCursor {
+	return nil
+}
+
+func createCursor(img *image.RGBA, xHot, yHot int) *GlfwCursor {
+	return nil
+}
+
+func setCursorMode(w *GlfwWindow, mode int) {}
+
+// The methods below are used by the code shared with the glfw builds,
+// but are never called, as there are no glfw windows or monitors.
+
+func (w *GlfwWindow) Destroy()                             {}
+func (w *GlfwWindow) Focus()                               {}
+func (w *GlfwWindow) GetContentScale() (float32, float32)  { return 1, 1 }
+func (w *GlfwWindow) GetCursorPos() (float64, float64)     { return 0, 0 }
+func (w *GlfwWindow) GetFrameSize() (int, int, int, int)   { return 0, 0, 0, 0 }
+func (w *GlfwWindow) GetMonitor() *GlfwMonitor             { return nil }
+func (w *GlfwWindow) GetPos() (int, int)                   { return 0, 0 }
+func (w *GlfwWindow) GetSize() (int, int)                  { return 0, 0 }
+func (w *GlfwWindow) Iconify()                             {}
+func (w *GlfwWindow) MakeContextCurrent()                  {}
+func (w *GlfwWindow) Maximize()                            {}
+func (w *GlfwWindow) Restore()                             {}
+func (w *GlfwWindow) SetCursor(c *GlfwCursor)              {}
+func (w *GlfwWindow) SetIcon(images []image.Image)         {}
+func (w *GlfwWindow) SetPos(x, y int)                      {}
+func (w *GlfwWindow) SetShouldClose(value bool)            {}
+func (w *GlfwWindow) SetSize(width, height int)            {}
+func (w *GlfwWindow) ShouldClose() bool                    { return false }
+func (w *GlfwWindow) Show()                                {}
+func (w *GlfwWindow) SwapBuffers()                         {}
+func (m *GlfwMonitor) GetContentScale() (float32, float32) { return 1, 1 }
+func (m *GlfwMonitor) GetName() string                     { return "" }
+func (m *GlfwMonitor) GetPhysicalSize() (int, int)         { return 0, 0 }
+func (m *GlfwMonitor) GetVideoMode() *VidMode              { return &VidMode{} }
+func (m *GlfwMonitor) GetWorkarea() (int, int, int, int)   { return 0, 0, 0, 0 }
+func (c *GlfwCursor) Destroy()                             {}
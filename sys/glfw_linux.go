@@ -1,84 +1,79 @@
+//go:build !headless
+
 // sys is the only package that depends on glfw.
 // glfw is only imported in glfw_linux.go or glfw_windows.go
-// Except for the imports, these files should be identical
+// Except for the imports, the cursors missing in glfw 3.3, the monitor callback
+// missing in purego-glfw and small differences in the glfw api (key names, video modes
+// and SetTitle), these files should be identical
 // Use "github.com/go-gl/glfw/v3.3/glfw"
 
 package sys
 
 import (
+	"image"
 	"log/slog"
-	"time"
 
 	"github.com/go-gl/glfw/v3.3/glfw"
-	"github.com/jkvatne/jkvgui/f32"
 )
 
 var (
 	Monitors []*glfw.Monitor
 )
 
-type HintDef struct {
-	WidgetRect f32.Rect // Original widgets size
-	Text       string
-	T          time.Time
-	Tag        any
-}
-
 type (
 	GlfwWindow  = glfw.Window
+	GlfwCursor  = glfw.Cursor
+	GlfwMonitor = glfw.Monitor
 	Key         = glfw.Key
 	Action      = glfw.Action
 	ModifierKey = glfw.ModifierKey
 	MouseButton = glfw.MouseButton
 )
 
-var (
-	pVResizeCursor   *glfw.Cursor
-	pHResizeCursor   *glfw.Cursor
-	pArrowCursor     *glfw.Cursor
-	pHandCursor      *glfw.Cursor
-	pCrosshairCursor *glfw.Cursor
-	pIBeamCursor     *glfw.Cursor
-)
-
 //goland:noinspection ALL,GoUnusedConst
 const (
-	KeyRight         = glfw.KeyRight
-	KeyLeft          = glfw.KeyLeft
-	KeyUp            = glfw.KeyUp
-	KeyDown          = glfw.KeyDown
-	KeyTab           = glfw.KeyTab
-	KeySpace         = glfw.KeySpace
-	KeyEnter         = glfw.KeyEnter
-	KeyKPEnter       = glfw.KeyKPEnter
-	KeyEscape        = glfw.KeyEscape
-	KeyBackspace     = glfw.KeyBackspace
-	KeyDelete        = glfw.KeyDelete
-	KeyHome          = glfw.KeyHome
-	KeyEnd           = glfw.KeyEnd
-	KeyPageUp        = glfw.KeyPageUp
-	KeyPageDown      = glfw.KeyPageDown
-	KeyInsert        = glfw.KeyInsert
-	KeyC             = glfw.KeyC
-	KeyV             = glfw.KeyV
-	KeyX             = glfw.KeyX
-	ModShift         = glfw.ModShift
-	ModControl       = glfw.ModControl
-	ModAlt           = glfw.ModAlt
-	Release          = glfw.Release
-	Press            = glfw.Press
-	Repeat           = glfw.Repeat
-	MouseButtonLeft  = glfw.MouseButtonLeft
-	MouseButtonRight = glfw.MouseButtonRight
-)
-
-const (
-	ArrowCursor     = int(glfw.ArrowCursor)
-	IBeamCursor     = int(glfw.IBeamCursor)
-	CrosshairCursor = int(glfw.CrosshairCursor)
-	HandCursor      = int(glfw.HandCursor)
-	HResizeCursor   = int(glfw.HResizeCursor)
-	VResizeCursor   = int(glfw.VResizeCursor)
+	KeyRight          = glfw.KeyRight
+	KeyLeft           = glfw.KeyLeft
+	KeyUp             = glfw.KeyUp
+	KeyDown           = glfw.KeyDown
+	KeyTab            = glfw.KeyTab
+	KeySpace          = glfw.KeySpace
+	KeyEnter          = glfw.KeyEnter
+	KeyKPEnter        = glfw.KeyKPEnter
+	KeyEscape         = glfw.KeyEscape
+	KeyBackspace      = glfw.KeyBackspace
+	KeyDelete         = glfw.KeyDelete
+	KeyHome           = glfw.KeyHome
+	KeyEnd            = glfw.KeyEnd
+	KeyPageUp         = glfw.KeyPageUp
+	KeyPageDown       = glfw.KeyPageDown
+	KeyInsert         = glfw.KeyInsert
+	KeyC              = glfw.KeyC
+	KeyV              = glfw.KeyV
+	KeyX              = glfw.KeyX
+	KeyA              = glfw.KeyA
+	Key0              = glfw.Key0
+	KeyF1             = glfw.KeyF1
+	KeyF10            = glfw.KeyF10
+	KeyF12            = glfw.KeyF12
+	KeyLeftShift      = glfw.KeyLeftShift
+	KeyRightShift     = glfw.KeyRightShift
+	KeyLeftControl    = glfw.KeyLeftControl
+	KeyRightControl   = glfw.KeyRightControl
+	KeyLeftAlt        = glfw.KeyLeftAlt
+	KeyRightAlt       = glfw.KeyRightAlt
+	KeyLeftSuper      = glfw.KeyLeftSuper
+	KeyRightSuper     = glfw.KeyRightSuper
+	ModShift          = glfw.ModShift
+	ModControl        = glfw.ModControl
+	ModAlt            = glfw.ModAlt
+	ModSuper          = glfw.ModSuper
+	Release           = glfw.Release
+	Press             = glfw.Press
+	Repeat            = glfw.Repeat
+	MouseButtonLeft   = glfw.MouseButtonLeft
+	MouseButtonRight  = glfw.MouseButtonRight
+	MouseButtonMiddle = glfw.MouseButtonMiddle
 )
 
 func WaitEventsTimeout(secondsDelay float32) {
@@ -115,6 +110,7 @@ func GetWindow(w *glfw.Window) *Window {
 
 func closeCallback(w *glfw.Window) {
 	slog.Debug("Close callback", "ShouldClose", w.ShouldClose())
+	GetWindow(w).HandleClose()
 }
 
 // keyCallback see https://www.glfw.org/docs/latest/window_guide.html
@@ -131,6 +127,11 @@ func btnCallback(w *glfw.Window, button glfw.MouseButton, action glfw.Action, mo
 	GetWindow(w).HandleMouseButton(button, action, mods)
 }
 
+// dropCallback is called from the glfw window handler when files are dropped on the window.
+func dropCallback(w *glfw.Window, names []string) {
+	GetWindow(w).HandleDrop(names)
+}
+
 // posCallback is called from the glfw window handler when the mouse moves.
 func posCallback(w *glfw.Window, xPos float64, yPos float64) {
 	GetWindow(w).HandleMousePos(xPos, yPos)
@@ -142,12 +143,15 @@ func scrollCallback(w *glfw.Window, xOff float64, yOff float64) {
 
 func sizeCallback(w *glfw.Window, width int, height int) {
 	slog.Debug("sizeCallback", "width", width, "height", height)
-	GetWindow(w).UpdateSize(width, height)
+	GetWindow(w).HandleSize(width, height)
 }
 
 func scaleCallback(w *glfw.Window, x float32, y float32) {
-	slog.Debug("scaleCallback", "x", x, "y", y)
-	GetWindow(w).UpdateSizeDp()
+	GetWindow(w).HandleScale(x, y)
+}
+
+func iconifyCallback(w *glfw.Window, iconified bool) {
+	GetWindow(w).updateState()
 }
 
 func SetDefaultHints() {
@@ -161,6 +165,9 @@ func SetDefaultHints() {
 }
 
 func SetMaximizedHint(maximized bool) {
+	if Headless {
+		return
+	}
 	if maximized {
 		glfw.WindowHint(glfw.Maximized, glfw.True)
 	} else {
@@ -168,10 +175,10 @@ func SetMaximizedHint(maximized bool) {
 	}
 }
 
-func createInvisibleWindow(w, h int, title string, monitor *glfw.Monitor) *glfw.Window {
+func createInvisibleWindow(w, h int, title string, monitor *glfw.Monitor, share *glfw.Window) *glfw.Window {
 	// Create invisible window so we can move it to correct monitor
 	glfw.WindowHint(glfw.Visible, glfw.False)
-	win, err := glfw.CreateWindow(w, h, title, monitor, nil)
+	win, err := glfw.CreateWindow(w, h, title, monitor, share)
 	if err != nil || win == nil {
 		panic(err)
 	}
@@ -179,21 +186,84 @@ func createInvisibleWindow(w, h int, title string, monitor *glfw.Monitor) *glfw.
 }
 
 func SetClipboardString(s string) {
+	if Headless {
+		clipboard = s
+		return
+	}
 	glfw.SetClipboardString(s)
 }
 
 func GetClipboardString() (string, error) {
+	if Headless {
+		return clipboard, nil
+	}
 	return glfw.GetClipboardString(), nil
 }
 
+func isMaximized(w *glfw.Window) bool {
+	return w.GetAttrib(glfw.Maximized) == glfw.True
+}
+
+// Deprecated: Use Window.Maximize
 func MaximizeWindow(w *glfw.Window) {
 	w.Maximize()
 }
 
+// Deprecated: Use Window.Minimize
 func MinimizeWindow(w *glfw.Window) {
 	w.Iconify()
 }
 
+func isIconified(w *glfw.Window) bool {
+	return w.GetAttrib(glfw.Iconified) == glfw.True
+}
+
+func isFloating(w *glfw.Window) bool {
+	return w.GetAttrib(glfw.Floating) == glfw.True
+}
+
+func setFloating(w *glfw.Window, on bool) {
+	if on {
+		w.SetAttrib(glfw.Floating, glfw.True)
+	} else {
+		w.SetAttrib(glfw.Floating, glfw.False)
+	}
+}
+
+func setTitle(w *glfw.Window, title string) {
+	w.SetTitle(title)
+}
+
+// setSizeLimits sets the limits in pixels. Zero is no limit.
+func setSizeLimits(w *glfw.Window, minW, minH, maxW, maxH int) {
+	dontCare := func(v int) int {
+		if v <= 0 {
+			return glfw.DontCare
+		}
+		return v
+	}
+	w.SetSizeLimits(dontCare(minW), dontCare(minH), dontCare(maxW), dontCare(maxH))
+}
+
+// setAspectRatio fixes the ratio between width and height. Zero removes the limit.
+func setAspectRatio(w *glfw.Window, numer, denom int) {
+	if numer <= 0 || denom <= 0 {
+		numer, denom = glfw.DontCare, glfw.DontCare
+	}
+	w.SetAspectRatio(numer, denom)
+}
+
+// setFullscreen makes the window fill the monitor, using its current video mode
+func setFullscreen(w *glfw.Window, m *glfw.Monitor) {
+	mode := m.GetVideoMode()
+	w.SetMonitor(m, 0, 0, mode.Width, mode.Height, mode.RefreshRate)
+}
+
+// setWindowed returns from full screen to a normal window with the given position and size
+func setWindowed(w *glfw.Window, x, y, width, height int) {
+	w.SetMonitor(nil, x, y, width, height, glfw.DontCare)
+}
+
 // PostEmptyEvent will post an empty event to the thread that initialized glfw.
 // This is normally the original thread running main().
 func PostEmptyEvent() {
@@ -201,9 +271,26 @@ func PostEmptyEvent() {
 }
 
 func glfwInit() error {
-	return glfw.Init()
+	if err := glfw.Init(); err != nil {
+		return err
+	}
+	glfw.SetMonitorCallback(monitorCallback)
+	return nil
+}
+
+func monitorCallback(m *glfw.Monitor, event glfw.PeripheralEvent) {
+	slog.Debug("Monitor callback", "Name", m.GetName(), "Connected", event == glfw.Connected)
+	updateMonitors()
 }
 
+// getKeyName returns the text produced by the key in the current keyboard layout
+func getKeyName(key Key, scancode int) string {
+	return glfw.GetKeyName(key, scancode)
+}
+
+// pollMonitors does nothing, as changes are reported by monitorCallback
+func pollMonitors() {}
+
 func DetachCurrentContext() {
 	glfw.DetachCurrentContext()
 }
@@ -226,13 +313,42 @@ func setCallbacks(Window *glfw.Window) {
 	Window.SetFocusCallback(focusCallback)
 	Window.SetCloseCallback(closeCallback)
 	Window.SetSizeCallback(sizeCallback)
+	Window.SetIconifyCallback(iconifyCallback)
+	Window.SetDropCallback(dropCallback)
 }
 
-func setupCursors() {
-	pArrowCursor = glfw.CreateStandardCursor(glfw.ArrowCursor)
-	pVResizeCursor = glfw.CreateStandardCursor(glfw.VResizeCursor)
-	pHResizeCursor = glfw.CreateStandardCursor(glfw.HResizeCursor)
-	pIBeamCursor = glfw.CreateStandardCursor(glfw.HResizeCursor)
-	pCrosshairCursor = glfw.CreateStandardCursor(glfw.HResizeCursor)
-	pHandCursor = glfw.CreateStandardCursor(glfw.HResizeCursor)
+// createStandardCursor returns the glfw cursor for one of the standard cursor ids
+func createStandardCursor(id int) *glfw.Cursor {
+	switch id {
+	case IBeamCursor:
+		return glfw.CreateStandardCursor(glfw.IBeamCursor)
+	case CrosshairCursor:
+		return glfw.CreateStandardCursor(glfw.CrosshairCursor)
+	case HandCursor:
+		return glfw.CreateStandardCursor(glfw.HandCursor)
+	case HResizeCursor:
+		return glfw.CreateStandardCursor(glfw.HResizeCursor)
+	case VResizeCursor:
+		return glfw.CreateStandardCursor(glfw.VResizeCursor)
+	case ResizeNWSECursor, ResizeNESWCursor, ResizeAllCursor:
+		// Not available in glfw 3.3
+		return glfw.CreateStandardCursor(glfw.CrosshairCursor)
+	default:
+		return glfw.CreateStandardCursor(glfw.ArrowCursor)
+	}
+}
+
+func createCursor(img *image.RGBA, xHot, yHot int) *glfw.Cursor {
+	return glfw.CreateCursor(img, xHot, yHot)
+}
+
+func setCursorMode(w *glfw.Window, mode int) {
+	switch mode {
+	case CursorHidden:
+		w.SetInputMode(glfw.CursorMode, glfw.CursorHidden)
+	case CursorDisabled:
+		w.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
+	default:
+		w.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
+	}
 }
@@ -0,0 +1,145 @@
+package sys
+
+import (
+	"flag"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/jkvatne/purego-glfw/gl"
+)
+
+// Headless is true when the windows are rendered into offscreen framebuffers
+// instead of visible glfw windows. No display is needed, so the tests and examples
+// can run on CI machines and servers. It is set by the -headless flag, by the
+// JKVGUI_HEADLESS environment variable, or by the application before calling Init().
+// It is always true when built with the headless tag, where glfw is not used.
+var Headless bool
+
+var headlessFlag = flag.Bool("headless", os.Getenv("JKVGUI_HEADLESS") != "", "Render offscreen, without a display")
+
+// HeadlessWidth and HeadlessHeight is the size of the virtual monitor used in headless mode.
+var (
+	HeadlessWidth  = 1920
+	HeadlessHeight = 1080
+)
+
+// HeadlessScale is the content scale of the virtual monitor, like 1.5 for a monitor with 150% scaling.
+var HeadlessScale float32 = 1.0
+
+// HeadlessSamples is the number of samples pr pixel used for anti-aliasing,
+// the same as requested for glfw windows.
+var HeadlessSamples int32 = 4
+
+// offscreen is the framebuffer used by a headless window. Drawing is done
+// into a multisampled framebuffer, that is resolved before the pixels are read.
+type offscreen struct {
+	fbo, rbo               uint32
+	resolveFbo, resolveRbo uint32
+	width, height          int
+	shouldClose            bool
+}
+
+var (
+	currentWindow  *Window
+	headlessWakeup = make(chan struct{}, 1)
+	clipboard      string
+)
+
+// createHeadlessWindow is used by CreateWindow in headless mode.
+func createHeadlessWindow(w, h int, name string, userScale float32) *Window {
+	win := &Window{offscreen: &offscreen{}}
+	win.Gd.ScaleX, win.Gd.ScaleY = win.contentScale()
+	if NoScaling {
+		win.Gd.ScaleX, win.Gd.ScaleY = 1.0, 1.0
+	}
+	if w <= 0 {
+		w = HeadlessWidth
+	} else {
+		w = min(int(float32(w)*win.Gd.ScaleX), HeadlessWidth)
+	}
+	if h <= 0 {
+		h = HeadlessHeight
+	} else {
+		h = min(int(float32(h)*win.Gd.ScaleY), HeadlessHeight)
+	}
+	win.UserScale = userScale
+	win.LeftBtnUpTime = time.Now()
+	win.UpdateSize(w, h)
+	WinListMutex.Lock()
+	WindowList = append(WindowList, win)
+	win.Wno = len(WindowList) - 1
+	WinListMutex.Unlock()
+	WindowCount.Add(1)
+	win.Name = name
+	win.Trigger = make(chan bool, 1)
+	win.Focused = true
+	win.mirrorKeys(true)
+	LoadOpenGl(win)
+	win.ClearMouseBtns()
+	if win.Wno == 0 {
+		win.startRecordOrPlay()
+	}
+	slog.Debug("CreateWindow() done", "Name", name, "Headless", true, "W", win.WidthPx, "H", win.HeightPx)
+	return win
+}
+
+func renderbuffer(samples int32, w, h int) uint32 {
+	var rbo uint32
+	gl.GenRenderbuffers(1, &rbo)
+	gl.BindRenderbuffer(gl.RENDERBUFFER, rbo)
+	gl.RenderbufferStorageMultisample(gl.RENDERBUFFER, samples, gl.RGBA8, int32(w), int32(h))
+	return rbo
+}
+
+func framebuffer(rbo uint32) uint32 {
+	var fbo uint32
+	gl.GenFramebuffers(1, &fbo)
+	gl.BindFramebuffer(gl.FRAMEBUFFER, fbo)
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, gl.COLOR_ATTACHMENT0, gl.RENDERBUFFER, rbo)
+	if s := gl.CheckFramebufferStatus(gl.FRAMEBUFFER); s != gl.FRAMEBUFFER_COMPLETE {
+		slog.Error("Offscreen framebuffer not complete", "Status", s)
+	}
+	return fbo
+}
+
+func (o *offscreen) delete() {
+	if o.fbo != 0 {
+		gl.DeleteFramebuffers(1, &o.fbo)
+		gl.DeleteRenderbuffers(1, &o.rbo)
+		gl.DeleteFramebuffers(1, &o.resolveFbo)
+		gl.DeleteRenderbuffers(1, &o.resolveRbo)
+		o.fbo = 0
+	}
+}
+
+// bind makes the offscreen framebuffer the target for drawing.
+// The buffers are created again when the window size has changed.
+func (o *offscreen) bind(w, h int) {
+	if o.fbo == 0 || o.width != w || o.height != h {
+		o.delete()
+		o.width, o.height = w, h
+		o.resolveRbo = renderbuffer(0, w, h)
+		o.resolveFbo = framebuffer(o.resolveRbo)
+		o.rbo = renderbuffer(HeadlessSamples, w, h)
+		o.fbo = framebuffer(o.rbo)
+		gl.Clear(gl.COLOR_BUFFER_BIT)
+	}
+	gl.BindFramebuffer(gl.FRAMEBUFFER, o.fbo)
+}
+
+// resolve copies the multisampled image to the resolve framebuffer,
+// and selects it for reading pixels.
+func (o *offscreen) resolve() {
+	scissor := gl.IsEnabled(gl.SCISSOR_TEST)
+	gl.Disable(gl.SCISSOR_TEST)
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, o.fbo)
+	gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, o.resolveFbo)
+	gl.BlitFramebuffer(0, 0, int32(o.width), int32(o.height), 0, 0, int32(o.width), int32(o.height),
+		gl.COLOR_BUFFER_BIT, gl.NEAREST)
+	gl.BindFramebuffer(gl.DRAW_FRAMEBUFFER, o.fbo)
+	gl.BindFramebuffer(gl.READ_FRAMEBUFFER, o.resolveFbo)
+	if scissor {
+		gl.Enable(gl.SCISSOR_TEST)
+	}
+}
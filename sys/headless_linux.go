@@ -0,0 +1,177 @@
+package sys
+
+/*
+#cgo LDFLAGS: -ldl
+#include <stdlib.h>
+#include <dlfcn.h>
+
+// libEGL is loaded when headless mode is started, so programs using glfw windows
+// do not need the EGL headers and library. The types and constants used are defined here.
+typedef void *EGLDisplay;
+typedef void *EGLContext;
+typedef void *EGLConfig;
+typedef void *EGLSurface;
+typedef int EGLint;
+typedef unsigned int EGLBoolean;
+typedef unsigned int EGLenum;
+
+#define EGL_FALSE 0
+#define EGL_TRUE 1
+#define EGL_NONE 0x3038
+#define EGL_OPENGL_API 0x30A2
+#define EGL_CONTEXT_MAJOR_VERSION 0x3098
+#define EGL_CONTEXT_MINOR_VERSION 0x30FB
+#define EGL_CONTEXT_OPENGL_PROFILE_MASK 0x30FD
+#define EGL_CONTEXT_OPENGL_CORE_PROFILE_BIT 0x00000001
+#define EGL_CONTEXT_OPENGL_FORWARD_COMPATIBLE 0x31B1
+#define EGL_PLATFORM_SURFACELESS_MESA 0x31DD
+
+static void *(*pGetProcAddress)(const char *);
+static EGLint (*pGetError)(void);
+static EGLDisplay (*pGetPlatformDisplay)(EGLenum, void *, const EGLint *);
+static EGLBoolean (*pInitialize)(EGLDisplay, EGLint *, EGLint *);
+static EGLBoolean (*pBindAPI)(EGLenum);
+static EGLContext (*pCreateContext)(EGLDisplay, EGLConfig, EGLContext, const EGLint *);
+static EGLBoolean (*pMakeCurrent)(EGLDisplay, EGLSurface, EGLSurface, EGLContext);
+static EGLBoolean (*pDestroyContext)(EGLDisplay, EGLContext);
+static EGLBoolean (*pTerminate)(EGLDisplay);
+
+// loadEgl opens libEGL and finds the functions used. It returns 0 on failure.
+static int loadEgl() {
+	void *lib = dlopen("libEGL.so.1", RTLD_NOW | RTLD_GLOBAL);
+	if (lib == NULL) {
+		return 0;
+	}
+	pGetProcAddress = dlsym(lib, "eglGetProcAddress");
+	pGetError = dlsym(lib, "eglGetError");
+	pInitialize = dlsym(lib, "eglInitialize");
+	pBindAPI = dlsym(lib, "eglBindAPI");
+	pCreateContext = dlsym(lib, "eglCreateContext");
+	pMakeCurrent = dlsym(lib, "eglMakeCurrent");
+	pDestroyContext = dlsym(lib, "eglDestroyContext");
+	pTerminate = dlsym(lib, "eglTerminate");
+	if (!pGetProcAddress || !pGetError || !pInitialize || !pBindAPI || !pCreateContext ||
+		!pMakeCurrent || !pDestroyContext || !pTerminate) {
+		return 0;
+	}
+	pGetPlatformDisplay = pGetProcAddress("eglGetPlatformDisplayEXT");
+	return pGetPlatformDisplay != NULL;
+}
+
+static EGLDisplay openSurfaceless() {
+	return pGetPlatformDisplay(EGL_PLATFORM_SURFACELESS_MESA, NULL, NULL);
+}
+
+static int noDisplay(EGLDisplay dpy) {
+	return dpy == NULL;
+}
+
+static EGLint getError() {
+	return pGetError();
+}
+
+static EGLBoolean initialize(EGLDisplay dpy) {
+	EGLint major, minor;
+	return pInitialize(dpy, &major, &minor);
+}
+
+static EGLBoolean bindOpenGL() {
+	return pBindAPI(EGL_OPENGL_API);
+}
+
+static EGLContext createContext(EGLDisplay dpy) {
+	EGLint attribs[] = {
+		EGL_CONTEXT_MAJOR_VERSION, 3,
+		EGL_CONTEXT_MINOR_VERSION, 3,
+		EGL_CONTEXT_OPENGL_PROFILE_MASK, EGL_CONTEXT_OPENGL_CORE_PROFILE_BIT,
+		EGL_CONTEXT_OPENGL_FORWARD_COMPATIBLE, EGL_TRUE,
+		EGL_NONE,
+	};
+	return pCreateContext(dpy, NULL, NULL, attribs);
+}
+
+static EGLBoolean makeCurrent(EGLDisplay dpy, EGLContext ctx) {
+	return pMakeCurrent(dpy, NULL, NULL, ctx);
+}
+
+static void destroy(EGLDisplay dpy, EGLContext ctx) {
+	if (ctx != NULL) {
+		pDestroyContext(dpy, ctx);
+	}
+	pTerminate(dpy);
+}
+
+static void *procAddress(const char *name) {
+	return pGetProcAddress(name);
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"github.com/jkvatne/purego-glfw/gl"
+)
+
+// The headless mode on Linux uses the Mesa EGL surfaceless platform.
+// It needs only libEGL and a Mesa driver (llvmpipe works fine), and no X11 or Wayland server.
+// libEGL is loaded at run time, so it is only needed when the headless mode is used.
+var (
+	eglDisplay C.EGLDisplay
+	eglContext C.EGLContext
+)
+
+func eglError(call string) error {
+	return fmt.Errorf("%s failed, EGL error 0x%x", call, int(C.getError()))
+}
+
+// initHeadless creates the window-less OpenGL context and loads the OpenGL functions.
+func initHeadless() error {
+	if C.loadEgl() == 0 {
+		return errors.New("libEGL.so.1 with eglGetPlatformDisplayEXT was not found")
+	}
+	eglDisplay = C.openSurfaceless()
+	if C.noDisplay(eglDisplay) != 0 {
+		return errors.New("the EGL surfaceless platform is not available")
+	}
+	if C.initialize(eglDisplay) == C.EGL_FALSE {
+		return eglError("eglInitialize")
+	}
+	if C.bindOpenGL() == C.EGL_FALSE {
+		return eglError("eglBindAPI")
+	}
+	eglContext = C.createContext(eglDisplay)
+	if eglContext == nil {
+		return eglError("eglCreateContext")
+	}
+	if err := headlessMakeCurrent(); err != nil {
+		return err
+	}
+	return gl.InitWithProcAddrFunc(func(name string) unsafe.Pointer {
+		cName := C.CString(name)
+		defer C.free(unsafe.Pointer(cName))
+		return C.procAddress(cName)
+	})
+}
+
+func headlessMakeCurrent() error {
+	if C.makeCurrent(eglDisplay, eglContext) == C.EGL_FALSE {
+		return eglError("eglMakeCurrent")
+	}
+	return nil
+}
+
+func headlessDetach() {
+	C.makeCurrent(eglDisplay, nil)
+}
+
+func terminateHeadless() {
+	if C.noDisplay(eglDisplay) != 0 {
+		return
+	}
+	headlessDetach()
+	C.destroy(eglDisplay, eglContext)
+	eglContext, eglDisplay = nil, 0
+}
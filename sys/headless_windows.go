@@ -0,0 +1,19 @@
+package sys
+
+import "errors"
+
+// The headless mode is only implemented on Linux, where it is used on CI machines without a display.
+
+func initHeadless() error {
+	return errors.New("headless mode is not supported on windows")
+}
+
+func headlessMakeCurrent() error {
+	return nil
+}
+
+func headlessDetach() {
+}
+
+func terminateHeadless() {
+}
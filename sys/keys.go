@@ -0,0 +1,104 @@
+package sys
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// HeadlessKeyNames gives the text produced by the keys in headless mode, where there
+// is no keyboard layout. It is used for testing other layouts. Keys not found here
+// give the letters and digits of the US layout.
+var HeadlessKeyNames = map[Key]string{}
+
+var modifierKeys = []struct {
+	mod  ModifierKey
+	keys [2]Key
+}{
+	{ModShift, [2]Key{KeyLeftShift, KeyRightShift}},
+	{ModControl, [2]Key{KeyLeftControl, KeyRightControl}},
+	{ModAlt, [2]Key{KeyLeftAlt, KeyRightAlt}},
+	{ModSuper, [2]Key{KeyLeftSuper, KeyRightSuper}},
+}
+
+// KeyIsDown mirrors the key state of the focused window.
+// Deprecated: Use Window.KeyDown, which gives the state for each window.
+var KeyIsDown [512]bool
+
+// keyWindow is the window mirrored in KeyIsDown
+var keyWindow *Window
+
+// KeyDown is true while the key is held down in the window.
+func (win *Window) KeyDown(key Key) bool {
+	return key >= 0 && int(key) < len(win.keyDown) && win.keyDown[key]
+}
+
+// ModsDown returns the modifier keys held down in the window.
+func (win *Window) ModsDown() ModifierKey {
+	var mods ModifierKey
+	for _, m := range modifierKeys {
+		if win.KeyDown(m.keys[0]) || win.KeyDown(m.keys[1]) {
+			mods |= m.mod
+		}
+	}
+	return mods
+}
+
+// ModDown is true while all the given modifiers are held down, like ModDown(ModControl|ModShift).
+func (win *Window) ModDown(mods ModifierKey) bool {
+	return win.ModsDown()&mods == mods
+}
+
+// setKeyDown updates the key state from a key event
+func (win *Window) setKeyDown(key Key, action Action) {
+	if key < 0 || int(key) >= len(win.keyDown) {
+		return
+	}
+	if action == Press {
+		win.keyDown[key] = true
+	} else if action == Release {
+		win.keyDown[key] = false
+	}
+	if win == keyWindow {
+		KeyIsDown[key] = win.keyDown[key]
+	}
+}
+
+// mirrorKeys makes KeyIsDown follow the window when it gets focus,
+// and clears it when the window loses focus.
+func (win *Window) mirrorKeys(focused bool) {
+	if focused {
+		keyWindow = win
+		KeyIsDown = win.keyDown
+	} else if keyWindow == win {
+		keyWindow = nil
+		KeyIsDown = [len(KeyIsDown)]bool{}
+	}
+}
+
+// KeyName returns the text produced by the key in the current keyboard layout, without
+// modifiers, like "a" or "ä". It is "" for keys that do not produce text.
+// The scancode is used when the key is unknown, and can be 0 for known keys.
+func KeyName(key Key, scancode int) string {
+	if Headless || !OpenGlStarted {
+		if name, ok := HeadlessKeyNames[key]; ok {
+			return name
+		}
+		switch {
+		case key >= KeyA && key < KeyA+26:
+			return string(rune('a' + key - KeyA))
+		case key >= Key0 && key <= Key0+9:
+			return string(rune('0' + key - Key0))
+		}
+		return ""
+	}
+	return getKeyName(key, scancode)
+}
+
+// keyChar returns the lower case character produced by the key, or 0
+func keyChar(key Key, scancode int) rune {
+	r, _ := utf8.DecodeRuneInString(KeyName(key, scancode))
+	if r == utf8.RuneError {
+		return 0
+	}
+	return unicode.ToLower(r)
+}
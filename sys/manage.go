@@ -0,0 +1,222 @@
+package sys
+
+import (
+	"image"
+	"log/slog"
+
+	"github.com/jkvatne/jkvgui/f32"
+	"github.com/jkvatne/jkvgui/gpu"
+)
+
+// WindowState is the way a window is shown on the screen
+type WindowState int
+
+const (
+	Normal WindowState = iota
+	Maximized
+	Minimized
+	Fullscreen
+)
+
+func (s WindowState) String() string {
+	switch s {
+	case Maximized:
+		return "Maximized"
+	case Minimized:
+		return "Minimized"
+	case Fullscreen:
+		return "Fullscreen"
+	}
+	return "Normal"
+}
+
+// State returns the current state of the window.
+// OnStateChange is called when it changes, also when changed by the user.
+func (win *Window) State() WindowState {
+	return win.state
+}
+
+// Maximize will make the window fill the work area of its monitor.
+func (win *Window) Maximize() {
+	if win.Window == nil {
+		win.setState(Maximized)
+		return
+	}
+	if win.state == Fullscreen {
+		win.Restore()
+	}
+	win.Window.Maximize()
+	win.updateState()
+}
+
+// Minimize will iconify the window.
+func (win *Window) Minimize() {
+	if win.Window == nil {
+		win.setState(Minimized)
+		return
+	}
+	win.Window.Iconify()
+	win.updateState()
+}
+
+// Restore returns a maximized, minimized or full screen window to its normal size and position.
+func (win *Window) Restore() {
+	if win.Window == nil {
+		win.setState(Normal)
+		return
+	}
+	if win.state == Fullscreen {
+		setWindowed(win.Window, win.windowed[0], win.windowed[1], win.windowed[2], win.windowed[3])
+	} else {
+		win.Window.Restore()
+	}
+	win.updateState()
+}
+
+// Fullscreen will let the window cover the whole monitor, without borders.
+// MonitorNo is 1 for the primary monitor, 2 for the secondary etc. Zero is the
+// monitor the window is on. Use Restore to go back to a normal window.
+func (win *Window) Fullscreen(monitorNo int) {
+	if win.Window == nil {
+		win.setState(Fullscreen)
+		return
+	}
+	if win.state != Fullscreen {
+		x, y := win.Window.GetPos()
+		w, h := win.Window.GetSize()
+		win.windowed = [4]int{x, y, w, h}
+	}
+	setFullscreen(win.Window, win.monitor(monitorNo))
+	win.updateState()
+}
+
+// MoveToMonitor will center the window on the given monitor, 1 being the primary.
+// A full screen window stays full screen on the new monitor.
+func (win *Window) MoveToMonitor(monitorNo int) {
+	if win.Window == nil {
+		return
+	}
+	if win.state == Fullscreen {
+		win.Fullscreen(monitorNo)
+		return
+	}
+	if win.state != Normal {
+		win.Window.Restore()
+	}
+	mx, my, mw, mh := win.monitor(monitorNo).GetWorkarea()
+	w, h := win.Window.GetSize()
+	win.Window.SetPos(mx+max(0, mw-w)/2, my+max(0, mh-h)/2)
+	win.updateState()
+}
+
+// monitor returns the monitor with the given number, or the one the window is on when it is 0.
+func (win *Window) monitor(monitorNo int) *GlfwMonitor {
+	if monitorNo <= 0 {
+		x, y := win.Window.GetPos()
+		w, h := win.Window.GetSize()
+		monitorNo = monitorAt(x+w/2, y+h/2)
+	}
+	monitors := GetMonitors()
+	return monitors[max(0, min(monitorNo-1, len(monitors)-1))]
+}
+
+// Title returns the text in the window's title bar.
+func (win *Window) Title() string {
+	if win.title == "" {
+		return win.Name
+	}
+	return win.title
+}
+
+// SetTitle changes the text in the window's title bar.
+// The Name used to save the window geometry is not changed.
+func (win *Window) SetTitle(title string) {
+	win.title = title
+	if win.Window != nil {
+		setTitle(win.Window, title)
+	}
+}
+
+// SetSizeLimits gives the minimum and maximum size of the window's client area, in dp.
+// A zero value is no limit.
+func (win *Window) SetSizeLimits(minW, minH, maxW, maxH float32) {
+	if win.Window == nil {
+		return
+	}
+	sx, sy := win.Gd.ScaleX, win.Gd.ScaleY
+	setSizeLimits(win.Window, int(minW*sx), int(minH*sy), int(maxW*sx), int(maxH*sy))
+}
+
+// SetAspectRatio keeps the ratio between the width and height when the user resizes the window.
+// Zero values will remove the restriction.
+func (win *Window) SetAspectRatio(w, h int) {
+	if win.Window == nil {
+		return
+	}
+	setAspectRatio(win.Window, w, h)
+}
+
+// SetAlwaysOnTop will keep the window above all other windows.
+func (win *Window) SetAlwaysOnTop(on bool) {
+	if win.Window != nil {
+		setFloating(win.Window, on)
+	}
+}
+
+// AlwaysOnTop is true when the window is kept above other windows.
+func (win *Window) AlwaysOnTop() bool {
+	return win.Window != nil && isFloating(win.Window)
+}
+
+// SetIconImages sets the icon shown in the title bar and task bar. The system will select
+// the image with the most suitable size, like 16x16, 32x32 or 48x48.
+// No images will give the default icon.
+func (win *Window) SetIconImages(images ...image.Image) {
+	if win.Window != nil {
+		win.Window.SetIcon(images)
+	}
+}
+
+// SetIcon uses the icon, drawn with the given color, as the window icon.
+func (win *Window) SetIcon(icon *gpu.Icon, c f32.Color) {
+	win.SetIconImages(icon.Image(16, c), icon.Image(32, c), icon.Image(48, c))
+}
+
+// HandleSize is called when the size of the window changes.
+func (win *Window) HandleSize(width, height int) {
+	win.UpdateSize(width, height)
+	win.updateState()
+	if win.OnResize != nil {
+		win.OnResize(win.WidthDp, win.HeightDp)
+	}
+	win.Invalidate()
+}
+
+// updateState reads the window's state from glfw, and calls OnStateChange if it has changed.
+func (win *Window) updateState() {
+	s := Normal
+	switch {
+	case win.Window == nil:
+		return
+	case win.Window.GetMonitor() != nil:
+		s = Fullscreen
+	case isIconified(win.Window):
+		s = Minimized
+	case isMaximized(win.Window):
+		s = Maximized
+	}
+	win.setState(s)
+}
+
+func (win *Window) setState(s WindowState) {
+	if s == win.state {
+		return
+	}
+	from := win.state
+	win.state = s
+	slog.Debug("Window state changed", "Wno", win.Wno+1, "From", from, "To", s)
+	if win.OnStateChange != nil {
+		win.OnStateChange(from, s)
+	}
+	win.Invalidate()
+}
@@ -0,0 +1,54 @@
+package sys
+
+import (
+	"log/slog"
+	"slices"
+)
+
+// OnMonitorChange is called when monitors are connected or disconnected.
+var OnMonitorChange func()
+
+// updateMonitors refreshes the list of monitors when it has changed.
+// Windows moved to a monitor with different scaling are updated.
+func updateMonitors() {
+	if Headless {
+		return
+	}
+	m := GetMonitors()
+	if slices.Equal(m, Monitors) {
+		return
+	}
+	slog.Debug("Monitors changed", "Count", len(m))
+	Monitors = m
+	WinListMutex.RLock()
+	list := slices.Clone(WindowList)
+	WinListMutex.RUnlock()
+	for _, w := range list {
+		w.updateScale()
+	}
+	if OnMonitorChange != nil {
+		OnMonitorChange()
+	}
+	Invalidate()
+}
+
+// HandleScale is called when the content scale of the window changes,
+// typically when it is moved to a monitor with another dpi.
+func (win *Window) HandleScale(x, y float32) {
+	slog.Debug("HandleScale", "x", x, "y", y)
+	win.updateScale()
+}
+
+// updateScale recalculates the window's scaling. The fonts for the new dpi are made
+// in the next frame, and OnScaleChange is called so the application can change its layout.
+func (win *Window) updateScale() {
+	sx, sy := win.Gd.ScaleX, win.Gd.ScaleY
+	win.UpdateSizeDp()
+	if sx != win.Gd.ScaleX || sy != win.Gd.ScaleY {
+		slog.Debug("Scale changed", "Wno", win.Wno+1, "ScaleX", win.Gd.ScaleX, "ScaleY", win.Gd.ScaleY)
+		if win.OnScaleChange != nil {
+			win.OnScaleChange()
+		}
+		win.Invalidate()
+	}
+}
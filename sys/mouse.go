@@ -23,8 +23,15 @@ func (win *Window) MousePos() f32.Pos {
 
 // StartDrag is called when a widget wants to handle mouse events even
 // outside its borders. Typically used when dragging a slider.
+// The drag ends when the button that is held down is released.
 func (win *Window) StartDrag() f32.Pos {
 	win.Dragging = true
+	win.DragButton = MouseButtonLeft
+	if !win.LeftBtnIsDown && win.RightBtnIsDown {
+		win.DragButton = MouseButtonRight
+	} else if !win.LeftBtnIsDown && win.MiddleBtnIsDown {
+		win.DragButton = MouseButtonMiddle
+	}
 	win.DragStartPos = win.mousePos
 	return win.mousePos
 }
@@ -90,7 +97,7 @@ func (win *Window) LeftBtnDoubleClick(r f32.Rect) bool {
 // RightBtnPressed is true if the mouse pointer is inside the
 // given rectangle and the btn is pressed,
 func (win *Window) RightBtnPressed(r f32.Rect) bool {
-	if win.SuppressEvents || !win.RightBtnIsDown || !win.mousePos.Inside(r) {
+	if win.SuppressEvents || win.Dragging && HasMoved(win.DragStartPos, win.MousePos()) || !win.RightBtnIsDown || !win.mousePos.Inside(r) {
 		return false
 	}
 	slog.Debug("RightBtnPressed", "MouseX", int(win.MousePos().X), "MouseY", int(win.MousePos().Y), "r.x", int(r.X), "r.y", int(r.Y), "r.W", int(r.W), "r.H", int(r.H))
@@ -116,17 +123,84 @@ func (win *Window) RightBtnClick(r f32.Rect) bool {
 	return false
 }
 
-// RightBtnDoubleClick indicates that the user is holding the Right btn down
-// independent of the mouse pointer location
+// RightBtnDoubleClick returns true if the Right btn has been double-clicked.
 func (win *Window) RightBtnDoubleClick(r f32.Rect) bool {
 	if !win.SuppressEvents && win.mousePos.Inside(r) && win.RightBtnDoubleClicked {
-		win.LeftBtnDoubleClicked = false
+		win.RightBtnDoubleClicked = false
 		slog.Debug("RightBtnDoubleClick:", "X", int(win.MousePos().X), "Y", int(win.MousePos().Y), "r.x", int(r.X), "r.y", int(r.Y), "r.W", int(r.W), "r.H", int(r.H))
 		return true
 	}
 	return false
 }
 
+// MiddleBtnPressed is true if the mouse pointer is inside the
+// given rectangle and the btn is pressed,
+func (win *Window) MiddleBtnPressed(r f32.Rect) bool {
+	if win.SuppressEvents || win.Dragging && HasMoved(win.DragStartPos, win.MousePos()) || !win.MiddleBtnIsDown || !win.mousePos.Inside(r) {
+		return false
+	}
+	slog.Debug("MiddleBtnPressed", "MouseX", int(win.MousePos().X), "MouseY", int(win.MousePos().Y), "r.x", int(r.X), "r.y", int(r.Y), "r.W", int(r.W), "r.H", int(r.H))
+	return true
+}
+
+// MiddleBtnDown indicates that the user is holding the middle btn down
+// independent of the mouse pointer location
+func (win *Window) MiddleBtnDown() bool {
+	if win.SuppressEvents {
+		return false
+	}
+	return win.MiddleBtnIsDown
+}
+
+// MiddleBtnClick returns true if the middle btn has been clicked.
+func (win *Window) MiddleBtnClick(r f32.Rect) bool {
+	if !win.SuppressEvents && win.mousePos.Inside(r) && time.Since(win.MiddleBtnDownTime) < LongPressTime && win.MiddleBtnClicked {
+		slog.Debug("MiddleBtnClick", "MouseX", int(win.MousePos().X), "MouseY", int(win.MousePos().Y), "r.x", int(r.X), "r.y", int(r.Y), "r.W", int(r.W), "r.H", int(r.H))
+		win.MiddleBtnClicked = false
+		return true
+	}
+	return false
+}
+
+// MiddleBtnDoubleClick returns true if the middle btn has been double-clicked.
+func (win *Window) MiddleBtnDoubleClick(r f32.Rect) bool {
+	if !win.SuppressEvents && win.mousePos.Inside(r) && win.MiddleBtnDoubleClicked {
+		win.MiddleBtnDoubleClicked = false
+		slog.Debug("MiddleBtnDoubleClick:", "X", int(win.MousePos().X), "Y", int(win.MousePos().Y), "r.x", int(r.X), "r.y", int(r.Y), "r.W", int(r.W), "r.H", int(r.H))
+		return true
+	}
+	return false
+}
+
+// longPress is true once when the button has been held down inside r for
+// more than LongPressTime, without moving the mouse.
+func (win *Window) longPress(r f32.Rect, button MouseButton, isDown bool, downTime time.Time) bool {
+	if win.SuppressEvents || !isDown || win.longPressed[button] || !win.mousePos.Inside(r) {
+		return false
+	}
+	if time.Since(downTime) < LongPressTime || HasMoved(win.btnDownPos[button], win.mousePos) {
+		return false
+	}
+	win.longPressed[button] = true
+	slog.Debug("LongPress", "Button", button, "X", int(win.MousePos().X), "Y", int(win.MousePos().Y))
+	return true
+}
+
+// LeftBtnLongPress is true once when the left btn is held down inside r for more than LongPressTime.
+func (win *Window) LeftBtnLongPress(r f32.Rect) bool {
+	return win.longPress(r, MouseButtonLeft, win.LeftBtnIsDown, win.LeftBtnDownTime)
+}
+
+// RightBtnLongPress is true once when the right btn is held down inside r for more than LongPressTime.
+func (win *Window) RightBtnLongPress(r f32.Rect) bool {
+	return win.longPress(r, MouseButtonRight, win.RightBtnIsDown, win.RightBtnDownTime)
+}
+
+// MiddleBtnLongPress is true once when the middle btn is held down inside r for more than LongPressTime.
+func (win *Window) MiddleBtnLongPress(r f32.Rect) bool {
+	return win.longPress(r, MouseButtonMiddle, win.MiddleBtnIsDown, win.MiddleBtnDownTime)
+}
+
 // ScrolledY returns the amount of pixels scrolled vertically since the last call to this function.
 // If gpu.SuppressEvents is true, the return value is always 0.0.
 func (win *Window) ScrolledY() float32 {
@@ -140,3 +214,18 @@ func (win *Window) ScrolledY() float32 {
 	win.ScrolledDistY = 0.0
 	return s
 }
+
+// ScrolledX returns the amount of pixels scrolled horizontally since the last call to this function.
+// Shift+scroll-wheel is reported as horizontal scrolling.
+// If gpu.SuppressEvents is true, the return value is always 0.0.
+func (win *Window) ScrolledX() float32 {
+	if !win.Focused {
+		return 0
+	}
+	if win.SuppressEvents {
+		return 0.0
+	}
+	s := win.ScrolledDistX
+	win.ScrolledDistX = 0.0
+	return s
+}
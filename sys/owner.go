@@ -0,0 +1,77 @@
+package sys
+
+// WindowOptions are optional settings for CreateWindow.
+type WindowOptions struct {
+	// Owner is the window owning the new window. An owned window is placed relative
+	// to the owner, on the same monitor, and it is closed together with the owner.
+	Owner *Window
+	// Modal will block all input to the owner while the window is open.
+	Modal bool
+}
+
+// Owner returns the window owning this window, or nil.
+func (win *Window) Owner() *Window {
+	return win.owner
+}
+
+// Modal is true for windows blocking input to their owner.
+func (win *Window) Modal() bool {
+	return win.modal && win.owner != nil
+}
+
+// OwnedWindows returns the open windows owned by this window.
+func (win *Window) OwnedWindows() []*Window {
+	WinListMutex.RLock()
+	defer WinListMutex.RUnlock()
+	var list []*Window
+	for _, w := range WindowList {
+		if w.owner == win {
+			list = append(list, w)
+		}
+	}
+	return list
+}
+
+// ModalChild returns the modal window blocking input to this window, or nil.
+// If the modal window has a modal window of its own, the innermost one is returned.
+func (win *Window) ModalChild() *Window {
+	var found *Window
+	for w := win; w != nil; {
+		var next *Window
+		for _, c := range w.OwnedWindows() {
+			if c.modal && !c.ShouldClose() {
+				next = c
+			}
+		}
+		if next != nil {
+			found = next
+		}
+		w = next
+	}
+	return found
+}
+
+// Blocked is true when a modal window owned by this window is open.
+func (win *Window) Blocked() bool {
+	return win.ModalChild() != nil
+}
+
+// focus will bring the window to front and give it input focus.
+func (win *Window) focus() {
+	if win.Window != nil {
+		win.Window.Focus()
+	}
+}
+
+// closeOwned is called when the window is destroyed. Owned windows are closed,
+// and a modal window will give the focus back to its owner.
+func (win *Window) closeOwned() {
+	for _, w := range win.OwnedWindows() {
+		w.SetShouldClose(true)
+	}
+	if win.Modal() && !win.owner.ShouldClose() {
+		win.owner.ClearMouseBtns()
+		win.owner.focus()
+		win.owner.Invalidate()
+	}
+}
@@ -0,0 +1,59 @@
+package sys
+
+import "sync"
+
+// Post queues the function f, to be called at the start of the next frame of the window.
+// It can be called from any goroutine, and will wake the event loop. Data shown by
+// the widgets should only be changed by functions posted this way, as the
+// widgets read the data without locking.
+func Post(win *Window, f func()) {
+	win.eventMutex.Lock()
+	win.posted = append(win.posted, f)
+	win.eventMutex.Unlock()
+	win.Invalidate()
+}
+
+// runPosted calls the functions queued by Post. It is called from StartFrame.
+func (win *Window) runPosted() {
+	win.eventMutex.Lock()
+	posted := win.posted
+	win.posted = nil
+	win.eventMutex.Unlock()
+	for _, f := range posted {
+		f()
+	}
+}
+
+var (
+	mainMutex sync.Mutex
+	mainQueue []func()
+)
+
+// RunOnMain queues the function f, to be called from PollEvents in the goroutine running
+// the event loop. It can be called from any goroutine, and will wake the event loop.
+// Calls that glfw only allows from the main thread, like creating windows, must be made
+// this way when the frames are drawn in other goroutines.
+func RunOnMain(f func()) {
+	mainMutex.Lock()
+	mainQueue = append(mainQueue, f)
+	mainMutex.Unlock()
+	wakeup()
+}
+
+// runOnMain calls the functions queued by RunOnMain. It is called from PollEvents.
+func runOnMain() {
+	mainMutex.Lock()
+	queue := mainQueue
+	mainQueue = nil
+	mainMutex.Unlock()
+	for _, f := range queue {
+		f()
+	}
+}
+
+// clearOnMain is called from Shutdown
+func clearOnMain() {
+	mainMutex.Lock()
+	mainQueue = nil
+	mainMutex.Unlock()
+}
@@ -0,0 +1,224 @@
+package sys
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	recordFile = flag.String("record", "", "Record the input events of the first window to the given file")
+	playFile   = flag.String("play", "", "Replay the input events in the given file in the first window")
+)
+
+// RecordedEvent is one input event as stored in a recording.
+// Frame is the frame where the event was handled, counted from 1 at the start
+// of the recording. T is the time since the recording was started.
+// Positions are in device independent pixels (Dp).
+type RecordedEvent struct {
+	Frame    int           `json:"frame"`
+	T        time.Duration `json:"t"`
+	Type     string        `json:"type"`
+	Key      Key           `json:"key,omitempty"`
+	Scancode int           `json:"scancode,omitempty"`
+	Action   Action        `json:"action,omitempty"`
+	Mods     ModifierKey   `json:"mods,omitempty"`
+	Rune     rune          `json:"rune,omitempty"`
+	Button   MouseButton   `json:"button,omitempty"`
+	X        float32       `json:"x,omitempty"`
+	Y        float32       `json:"y,omitempty"`
+	Dx       float32       `json:"dx,omitempty"`
+	Dy       float32       `json:"dy,omitempty"`
+	Focused  bool          `json:"focused,omitempty"`
+	Paths    []string      `json:"paths,omitempty"`
+}
+
+// recorder writes the events to a file, one json object pr line.
+// Each event is written immediately, so nothing is lost if the program crashes.
+type recorder struct {
+	mutex      sync.Mutex
+	file       *os.File
+	enc        *json.Encoder
+	start      time.Time
+	startFrame int
+}
+
+// player feeds the recorded events back to the window, frame by frame.
+type player struct {
+	events     []RecordedEvent
+	next       int
+	startFrame int
+}
+
+// StartRecording will write all input events for the window to the given file.
+// A running recording is stopped first.
+func (win *Window) StartRecording(filename string) error {
+	if err := win.StopRecording(); err != nil {
+		return err
+	}
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	slog.Info("Start recording", "Window", win.Name, "File", filename)
+	win.recMutex.Lock()
+	win.recorder = &recorder{file: f, enc: json.NewEncoder(f), start: time.Now(), startFrame: win.frames}
+	win.recMutex.Unlock()
+	return nil
+}
+
+// StopRecording closes the recording file. It does nothing if no recording is running.
+func (win *Window) StopRecording() error {
+	win.recMutex.Lock()
+	r := win.recorder
+	win.recorder = nil
+	win.recMutex.Unlock()
+	if r == nil {
+		return nil
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.file.Close()
+}
+
+func (win *Window) stopRecording() {
+	if err := win.StopRecording(); err != nil {
+		slog.Error("Could not close recording", "Error", err)
+	}
+}
+
+// Recording is true while the input events are recorded
+func (win *Window) Recording() bool {
+	win.recMutex.Lock()
+	defer win.recMutex.Unlock()
+	return win.recorder != nil
+}
+
+// record is called from the event handlers, before the event is used.
+func (win *Window) record(e RecordedEvent) {
+	win.recMutex.Lock()
+	r := win.recorder
+	win.recMutex.Unlock()
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	// The event will be handled in the next frame
+	e.Frame = win.frames - r.startFrame + 1
+	e.T = time.Since(r.start)
+	if err := r.enc.Encode(e); err != nil {
+		slog.Error("Recording failed", "Error", err)
+	}
+}
+
+// LoadRecording reads the events from a file written by the recorder.
+func LoadRecording(filename string) ([]RecordedEvent, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	var events []RecordedEvent
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e RecordedEvent
+		if err = json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", filename, line, err)
+		}
+		events = append(events, e)
+	}
+	return events, scanner.Err()
+}
+
+// Play loads the recording in the file and replays it in the window.
+func (win *Window) Play(filename string) error {
+	events, err := LoadRecording(filename)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return errors.New("no events in " + filename)
+	}
+	slog.Info("Start replay", "Window", win.Name, "File", filename, "Events", len(events))
+	win.PlayEvents(events)
+	return nil
+}
+
+// PlayEvents will replay the events, starting in the next frame.
+// The events are injected by the Sim functions at the start of each frame.
+func (win *Window) PlayEvents(events []RecordedEvent) {
+	win.player = &player{events: events, startFrame: win.frames}
+	win.Invalidate()
+}
+
+// Playing is true until all events in the replay are used.
+func (win *Window) Playing() bool {
+	return win.player != nil
+}
+
+// replay is called from StartFrame. It injects the events belonging to the frame.
+func (win *Window) replay() {
+	p := win.player
+	if p == nil {
+		return
+	}
+	frame := win.frames - p.startFrame
+	for p.next < len(p.events) && p.events[p.next].Frame <= frame {
+		win.simEvent(&p.events[p.next])
+		p.next++
+	}
+	if p.next >= len(p.events) {
+		slog.Info("Replay done", "Window", win.Name)
+		win.player = nil
+		return
+	}
+	// Make sure the next frame is drawn, even without any real events
+	win.Invalidate()
+}
+
+func (win *Window) simEvent(e *RecordedEvent) {
+	switch e.Type {
+	case "key":
+		win.SimKeyEvent(e.Key, e.Scancode, e.Action, e.Mods)
+	case "char":
+		win.SimChar(e.Rune)
+	case "button":
+		win.SimMouseButton(e.Button, e.Action, e.Mods, e.X, e.Y)
+	case "pos":
+		win.SimPos(e.X, e.Y)
+	case "scroll":
+		win.SimScroll(e.Dx, e.Dy, e.Mods)
+	case "focus":
+		win.SimFocus(e.Focused)
+	case "drop":
+		win.SimDrop(e.X, e.Y, e.Paths)
+	default:
+		slog.Error("Unknown event in replay", "Type", e.Type, "Frame", e.Frame)
+	}
+}
+
+// startRecordOrPlay is called when the first window is created, and
+// starts recording or replay if given on the command line.
+func (win *Window) startRecordOrPlay() {
+	if *recordFile != "" {
+		if err := win.StartRecording(*recordFile); err != nil {
+			slog.Error("Could not start recording", "File", *recordFile, "Error", err)
+		}
+	}
+	if *playFile != "" {
+		if err := win.Play(*playFile); err != nil {
+			slog.Error("Could not start replay", "File", *playFile, "Error", err)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package sys
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/jkvatne/jkvgui/gpu"
+)
+
+// Shortcut is a key combined with modifier keys, like Ctrl+S or F5.
+// When Char is not 0, the shortcut matches the key producing this character in the
+// keyboard layout, so Ctrl+Z is the key labelled Z also on AZERTY and Dvorak keyboards.
+// Key is used when the character produced is not known, or is not a Latin letter,
+// so the shortcuts work by key position on Cyrillic and Greek layouts.
+type Shortcut struct {
+	Key  Key
+	Char rune
+	Mods ModifierKey
+}
+
+// Binding connects a shortcut to an action.
+// If Scope is not nil, the binding is only active when the focused widget
+// was drawn inside the scope with the same tag (see PushScope).
+// The action is only run when the key is not used by the focused widget.
+type Binding struct {
+	Shortcut Shortcut
+	Action   func()
+	Scope    any
+}
+
+// Shortcuts is a registry of key bindings.
+type Shortcuts struct {
+	mutex    sync.Mutex
+	bindings []*Binding
+}
+
+// GlobalShortcuts are active in all windows. Bindings in the window's own
+// registry take precedence over the global ones.
+var GlobalShortcuts Shortcuts
+
+var ErrShortcutConflict = errors.New("shortcut conflict")
+
+const modMask = ModShift | ModControl | ModAlt | ModSuper
+
+var keyNames = map[Key]string{
+	KeyRight:     "Right",
+	KeyLeft:      "Left",
+	KeyUp:        "Up",
+	KeyDown:      "Down",
+	KeyTab:       "Tab",
+	KeySpace:     "Space",
+	KeyEnter:     "Enter",
+	KeyKPEnter:   "KPEnter",
+	KeyEscape:    "Esc",
+	KeyBackspace: "Backspace",
+	KeyDelete:    "Del",
+	KeyHome:      "Home",
+	KeyEnd:       "End",
+	KeyPageUp:    "PgUp",
+	KeyPageDown:  "PgDn",
+	KeyInsert:    "Ins",
+}
+
+var keyAliases = map[string]Key{
+	"ESCAPE":   KeyEscape,
+	"RETURN":   KeyEnter,
+	"DELETE":   KeyDelete,
+	"INSERT":   KeyInsert,
+	"PAGEUP":   KeyPageUp,
+	"PAGEDOWN": KeyPageDown,
+}
+
+// ParseShortcut converts a text like "Ctrl+Shift+F" or "F5" to a Shortcut.
+// Letters are matched by the character produced, other keys by their position.
+func ParseShortcut(s string) (Shortcut, error) {
+	var sc Shortcut
+	parts := strings.Split(s, "+")
+	for i, part := range parts {
+		p := strings.ToUpper(strings.TrimSpace(part))
+		if i < len(parts)-1 {
+			switch p {
+			case "CTRL", "CONTROL":
+				sc.Mods |= ModControl
+			case "SHIFT":
+				sc.Mods |= ModShift
+			case "ALT":
+				sc.Mods |= ModAlt
+			case "SUPER", "CMD", "WIN":
+				sc.Mods |= ModSuper
+			default:
+				return Shortcut{}, fmt.Errorf("unknown modifier %q in shortcut %q", part, s)
+			}
+			continue
+		}
+		key, ok := parseKey(p)
+		if !ok {
+			return Shortcut{}, fmt.Errorf("unknown key %q in shortcut %q", part, s)
+		}
+		sc.Key = key
+		if key >= KeyA && key < KeyA+26 {
+			sc.Char = rune('a' + key - KeyA)
+		}
+	}
+	return sc, nil
+}
+
+func parseKey(p string) (Key, bool) {
+	if len(p) == 1 && p[0] >= 'A' && p[0] <= 'Z' {
+		return KeyA + Key(p[0]-'A'), true
+	}
+	if len(p) == 1 && p[0] >= '0' && p[0] <= '9' {
+		return Key0 + Key(p[0]-'0'), true
+	}
+	if len(p) >= 2 && p[0] == 'F' {
+		n := 0
+		if _, err := fmt.Sscanf(p[1:], "%d", &n); err == nil && n >= 1 && n <= 12 {
+			return KeyF1 + Key(n-1), true
+		}
+	}
+	if k, ok := keyAliases[p]; ok {
+		return k, true
+	}
+	for k, name := range keyNames {
+		if strings.ToUpper(name) == p {
+			return k, true
+		}
+	}
+	return 0, false
+}
+
+// KeyText returns the name of the key as used in shortcut texts
+func KeyText(key Key) string {
+	switch {
+	case key >= KeyA && key < KeyA+26:
+		return string(rune('A' + key - KeyA))
+	case key >= Key0 && key <= Key0+9:
+		return string(rune('0' + key - Key0))
+	case key >= KeyF1 && key <= KeyF12:
+		return fmt.Sprintf("F%d", key-KeyF1+1)
+	}
+	if name, ok := keyNames[key]; ok {
+		return name
+	}
+	return fmt.Sprintf("Key%d", key)
+}
+
+// String returns the shortcut as a text like "Ctrl+Shift+F"
+func (s Shortcut) String() string {
+	var b strings.Builder
+	if s.Mods&ModControl != 0 {
+		b.WriteString("Ctrl+")
+	}
+	if s.Mods&ModShift != 0 {
+		b.WriteString("Shift+")
+	}
+	if s.Mods&ModAlt != 0 {
+		b.WriteString("Alt+")
+	}
+	if s.Mods&ModSuper != 0 {
+		b.WriteString("Super+")
+	}
+	if s.Char != 0 {
+		b.WriteRune(unicode.ToUpper(s.Char))
+	} else {
+		b.WriteString(KeyText(s.Key))
+	}
+	return b.String()
+}
+
+// Matches is true if the event is a typed key equal to the shortcut.
+func (s Shortcut) Matches(e *Event) bool {
+	if !e.Typed() || e.Mods&modMask != s.Mods {
+		return false
+	}
+	if s.Char != 0 && e.Char >= 'a' && e.Char <= 'z' {
+		return unicode.ToLower(s.Char) == e.Char
+	}
+	return e.Key == s.Key
+}
+
+// Hint returns the hint text with the shortcut appended, for use in Btn hints.
+func (b *Binding) Hint(text string) string {
+	if text == "" {
+		return b.Shortcut.String()
+	}
+	return text + " (" + b.Shortcut.String() + ")"
+}
+
+// Bind will add a binding of the chord (like "Ctrl+S") to the action.
+// The scope is the tag of the widget group where the binding is active, or nil.
+// An error is returned if the chord can not be parsed, or if it is already bound in the same scope.
+func (s *Shortcuts) Bind(chord string, action func(), scope any) (*Binding, error) {
+	sc, err := ParseShortcut(chord)
+	if err != nil {
+		return nil, err
+	}
+	return s.BindShortcut(sc, action, scope)
+}
+
+// BindShortcut is like Bind, but takes a Shortcut instead of a text.
+func (s *Shortcuts) BindShortcut(sc Shortcut, action func(), scope any) (*Binding, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, b := range s.bindings {
+		if b.Shortcut == sc && gpu.TagsEqual(b.Scope, scope) {
+			slog.Error("Shortcut conflict", "Shortcut", sc.String())
+			return b, fmt.Errorf("%w: %s is already bound", ErrShortcutConflict, sc.String())
+		}
+	}
+	b := &Binding{Shortcut: sc, Action: action, Scope: scope}
+	s.bindings = append(s.bindings, b)
+	return b, nil
+}
+
+// Unbind removes the binding from the registry
+func (s *Shortcuts) Unbind(b *Binding) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for i := range s.bindings {
+		if s.bindings[i] == b {
+			s.bindings = append(s.bindings[:i], s.bindings[i+1:]...)
+			return
+		}
+	}
+}
+
+// find returns the binding for the event. Bindings with a scope containing
+// the focused widget are preferred over bindings without scope.
+func (s *Shortcuts) find(win *Window, e *Event) *Binding {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var found *Binding
+	for _, b := range s.bindings {
+		if !b.Shortcut.Matches(e) {
+			continue
+		}
+		if b.Scope != nil && win.FocusInScope(b.Scope) {
+			return b
+		} else if b.Scope == nil && found == nil {
+			found = b
+		}
+	}
+	return found
+}
+
+// handleShortcuts is called from EndFrame, after the widgets and the focus navigation
+// have taken their keys. It runs the actions for the remaining typed keys that are
+// bound in the window or in the global registry, so a focused widget like Edit
+// gets Ctrl+C or Left before any binding for the same keys.
+func (win *Window) handleShortcuts() {
+	if win.DialogVisible {
+		return
+	}
+	for _, e := range win.KeyEvents() {
+		if !e.Typed() {
+			continue
+		}
+		b := win.Shortcuts.find(win, e)
+		if b == nil {
+			b = GlobalShortcuts.find(win, e)
+		}
+		if b != nil && b.Action != nil {
+			slog.Debug("Shortcut", "Shortcut", b.Shortcut.String())
+			e.Consume()
+			b.Action()
+			win.Invalidate()
+		}
+	}
+}
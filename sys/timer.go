@@ -0,0 +1,180 @@
+package sys
+
+import (
+	"sync"
+	"time"
+)
+
+// Timer is a function scheduled by AfterFunc or Every.
+// The function is called from PollEvents, in the goroutine running the event loop.
+// When the frames are drawn in other goroutines, the function should use Post
+// to change the data displayed by the widgets.
+type Timer struct {
+	when   time.Time
+	period time.Duration
+	f      func()
+}
+
+var (
+	timerMutex sync.Mutex
+	timers     []*Timer
+	blinkTimer *Timer
+)
+
+// AfterFunc will call f once, after the duration d.
+// The event loop is woken, so f is called even when there are no input events.
+func AfterFunc(d time.Duration, f func()) *Timer {
+	return addTimer(&Timer{when: time.Now().Add(d), f: f})
+}
+
+// Every will call f repeatedly, with the period d, until the timer is stopped.
+func Every(d time.Duration, f func()) *Timer {
+	return addTimer(&Timer{when: time.Now().Add(d), period: max(d, time.Millisecond), f: f})
+}
+
+func addTimer(t *Timer) *Timer {
+	timerMutex.Lock()
+	timers = append(timers, t)
+	timerMutex.Unlock()
+	wakeup()
+	return t
+}
+
+// Stop removes the timer. It returns false if the timer already has fired or was stopped.
+func (t *Timer) Stop() bool {
+	timerMutex.Lock()
+	defer timerMutex.Unlock()
+	for i := range timers {
+		if timers[i] == t {
+			timers = append(timers[:i], timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// AnimateUntil requests new frames for the window at the maximum frame rate until the time t.
+// When no animations are running, the event loop will wait for input events or timers.
+func (win *Window) AnimateUntil(t time.Time) {
+	timerMutex.Lock()
+	if t.After(win.animateUntil) {
+		win.animateUntil = t
+	}
+	timerMutex.Unlock()
+	wakeup()
+}
+
+// Animating is true while frames are requested by AnimateUntil.
+func (win *Window) Animating() bool {
+	timerMutex.Lock()
+	defer timerMutex.Unlock()
+	return time.Now().Before(win.animateUntil)
+}
+
+// nextWakeup returns the time PollEvents should wait for events.
+// It is shortened to the first timer, and is 0 when an animation is running.
+func nextWakeup(wait time.Duration) time.Duration {
+	now := time.Now()
+	WinListMutex.RLock()
+	defer WinListMutex.RUnlock()
+	timerMutex.Lock()
+	defer timerMutex.Unlock()
+	for _, w := range WindowList {
+		if now.Before(w.animateUntil) {
+			return 0
+		}
+	}
+	for _, t := range timers {
+		wait = min(wait, t.when.Sub(now))
+	}
+	return max(0, wait)
+}
+
+// runTimers calls the functions for all timers that are due.
+// Periodic timers are rescheduled, the others are removed.
+func runTimers() {
+	now := time.Now()
+	var due []*Timer
+	timerMutex.Lock()
+	for i := 0; i < len(timers); i++ {
+		t := timers[i]
+		if t.when.After(now) {
+			continue
+		}
+		due = append(due, t)
+		if t.period > 0 {
+			t.when = t.when.Add(t.period)
+			if t.when.Before(now) {
+				t.when = now.Add(t.period)
+			}
+		} else {
+			timers = append(timers[:i], timers[i+1:]...)
+			i--
+		}
+	}
+	timerMutex.Unlock()
+	for _, t := range due {
+		t.f()
+	}
+}
+
+// clearTimers is called from Shutdown
+func clearTimers() {
+	timerMutex.Lock()
+	timers = nil
+	blinkTimer = nil
+	timerMutex.Unlock()
+}
+
+// wakeup will make PollEvents return, so the wait time is recalculated.
+func wakeup() {
+	if Headless {
+		select {
+		case headlessWakeup <- struct{}{}:
+		default:
+		}
+		return
+	}
+	if OpenGlStarted {
+		PostEmptyEvent()
+	}
+}
+
+// startBlinking is called at the end of a frame where a text cursor was drawn.
+// The cursor blinks until no window has a focused Edit.
+func startBlinking() {
+	timerMutex.Lock()
+	running := blinkTimer != nil
+	timerMutex.Unlock()
+	if running {
+		return
+	}
+	BlinkState.Store(true)
+	t := Every(time.Second/time.Duration(BlinkFrequency*2), blink)
+	timerMutex.Lock()
+	blinkTimer = t
+	timerMutex.Unlock()
+}
+
+func blink() {
+	WinListMutex.RLock()
+	blinking := false
+	for _, w := range WindowList {
+		blinking = blinking || w.Blinking.Load()
+	}
+	WinListMutex.RUnlock()
+	if !blinking {
+		timerMutex.Lock()
+		t := blinkTimer
+		blinkTimer = nil
+		timerMutex.Unlock()
+		if t != nil {
+			t.Stop()
+		}
+		// Show the cursor immediately when an Edit gets focus again
+		BlinkState.Store(true)
+		return
+	}
+	BlinkState.Store(!BlinkState.Load())
+	Invalidate()
+}
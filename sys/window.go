@@ -20,54 +20,116 @@ import (
 
 var logLevel = flag.Int("loglevel", 8, "Set log level (8=Error, 4=Warning, 0=Info(default), -4=Debug)")
 
+// HintDef is the hint for the widget under the mouse pointer.
+type HintDef struct {
+	WidgetRect f32.Rect // Original widgets size
+	Text       string
+	T          time.Time
+	Tag        any
+}
+
 // Window variables.
 type Window struct {
-	Window                *GlfwWindow
-	Name                  string
-	Wno                   int
-	UserScale             float32
-	Mutex                 sync.Mutex
-	Trigger               chan bool
-	HintActive            bool
-	Focused               bool
-	Blinking              atomic.Bool
-	Cursor                int
-	CurrentTag            interface{}
-	PrevTag               interface{}
-	LastTag               interface{}
-	MoveToNext            bool
-	MoveToPrevious        bool
-	ToNext                bool
-	SuppressEvents        bool
-	mousePos              f32.Pos
-	Dragging              bool
-	DragStartPos          f32.Pos
-	LeftBtnIsDown         bool
-	LeftBtnDownTime       time.Time
-	LeftBtnUpTime         time.Time
-	LeftBtnDoubleClicked  bool
-	LeftBtnClicked        bool
-	RightBtnIsDown        bool
-	RightBtnDownTime      time.Time
-	RightBtnUpTime        time.Time
-	RightBtnDoubleClicked bool
-	RightBtnClicked       bool
-	ScrolledDistY         float32
-	DialogVisible         bool
-	redraws               int
-	fps                   float64
-	redrawStart           time.Time
-	LastRune              rune
-	LastKey               Key
-	LastMods              ModifierKey
-	NoScaling             bool
-	CurrentHint           HintDef
-	DeferredFunctions     []func()
-	HeightPx              int
-	HeightDp              float32
-	WidthPx               int
-	WidthDp               float32
-	Gd                    gpu.GlData
+	Window                 *GlfwWindow
+	Name                   string
+	Wno                    int
+	UserScale              float32
+	Mutex                  sync.Mutex // Deprecated: Use Post to change data from other goroutines. Memo and Edit lock it until it is removed.
+	Trigger                chan bool
+	HintActive             bool
+	Focused                bool
+	Blinking               atomic.Bool
+	Cursor                 int
+	customCursor           *Cursor
+	currentCursor          *GlfwCursor
+	cursorMode             int
+	CurrentTag             interface{}
+	PrevTag                interface{}
+	LastTag                interface{}
+	MoveToNext             bool
+	MoveToPrevious         bool
+	ToNext                 bool // Deprecated: Use MoveToNext.
+	SuppressEvents         bool
+	mousePos               f32.Pos
+	Dragging               bool
+	DragStartPos           f32.Pos
+	LeftBtnIsDown          bool
+	LeftBtnDownTime        time.Time
+	LeftBtnUpTime          time.Time
+	LeftBtnDoubleClicked   bool
+	LeftBtnClicked         bool
+	RightBtnIsDown         bool
+	RightBtnDownTime       time.Time
+	RightBtnUpTime         time.Time
+	RightBtnDoubleClicked  bool
+	RightBtnClicked        bool
+	MiddleBtnIsDown        bool
+	MiddleBtnDownTime      time.Time
+	MiddleBtnUpTime        time.Time
+	MiddleBtnDoubleClicked bool
+	MiddleBtnClicked       bool
+	DragButton             MouseButton
+	btnDownPos             [3]f32.Pos
+	longPressed            [3]bool
+	gestures               []*gestureState
+	dragTag                any
+	ScrolledDistY          float32
+	ScrolledDistX          float32
+	DialogVisible          bool
+	redraws                int
+	fps                    float64
+	redrawStart            time.Time
+	LastRune               rune
+	LastKey                Key
+	LastMods               ModifierKey
+	keyDown                [512]bool
+	NoScaling              bool
+	CurrentHint            HintDef
+	DeferredFunctions      []func()
+	unusedKeyHandlers      []func()
+	eventMutex             sync.Mutex
+	pendingEvents          []Event
+	posted                 []func()
+	events                 []Event
+	Shortcuts              Shortcuts
+	DroppedFiles           []string
+	DropPos                f32.Pos
+	scopes                 []any
+	focusScopes            []any
+	nextFocusScopes        []any
+	prevFocusScopes        []any
+	traps                  []any
+	focusChain             []focusItem
+	navGroups              []navGroup
+	navPath                []navLevel
+	tabIndexes             []tabIndex
+	focusRequest           any
+	lastFocus              any
+	previousFocus          any
+	focusChanged           bool
+	OnFocusChange          func(from, to any)
+	OnCloseRequest         func() bool
+	OnScaleChange          func()
+	OnStateChange          func(from, to WindowState)
+	OnResize               func(w, h float32)
+	frames                 int
+	recMutex               sync.Mutex
+	recorder               *recorder
+	player                 *player
+	offscreen              *offscreen
+	owner                  *Window
+	animateUntil           time.Time
+	modal                  bool
+	state                  WindowState
+	title                  string
+	windowed               [4]int
+	accessNodes            []*AccessNode
+	accessTree             *AccessTree
+	HeightPx               int
+	HeightDp               float32
+	WidthPx                int
+	WidthDp                float32
+	Gd                     gpu.GlData
 }
 
 var (
@@ -77,10 +139,11 @@ var (
 	WindowCount   atomic.Int32
 	WinListMutex  sync.RWMutex
 	MinFrameDelay = time.Second / 25
+	// MaxFrameDelay is the longest time between frames. Set it to 0 to draw no frames
+	// until there are input events, timers or calls to Invalidate.
 	MaxFrameDelay = time.Second
 	LastPollTime  time.Time
 	OpenGlStarted bool
-	KeyIsDown     [512]bool
 )
 
 // CreateWindow initializes glfw and returns a Window to use.
@@ -92,10 +155,37 @@ var (
 // - Small window of a given size, shrunk if the screen is not big enough (h=200, w=200)
 // - Use full screen height, but limit width (h=0, w=800)
 // - Use full screen width, but limit height (h=800, w=0)
-func CreateWindow(x, y, w, h int, name string, monitorNo int, userScale float32) *Window {
+//
+// The options can give an owner window. The owned window is then placed on the
+// owner's monitor, with x and y relative to the owner's client area, or centered
+// on the owner when they are negative. A userScale of 0 will use the owner's scale,
+// or 1 when there is no owner.
+func CreateWindow(x, y, w, h int, name string, monitorNo int, userScale float32, options ...WindowOptions) *Window {
 	slog.Debug("CreateWindow()", "Name", name, "Width", w, "Height", h)
-	win := &Window{}
-	m := Monitors[max(0, min(monitorNo-1, len(Monitors)-1))]
+	var opt WindowOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	if userScale <= 0 {
+		userScale = 1.0
+		if opt.Owner != nil {
+			userScale = opt.Owner.UserScale
+		}
+	}
+	if Headless {
+		win := createHeadlessWindow(w, h, name, userScale)
+		win.owner, win.modal = opt.Owner, opt.Modal
+		return win
+	}
+	win := &Window{owner: opt.Owner, modal: opt.Modal}
+	monitors := GetMonitors()
+	m := monitors[max(0, min(monitorNo-1, len(monitors)-1))]
+	var ownerX, ownerY, ownerW, ownerH int
+	if opt.Owner != nil {
+		ownerX, ownerY = opt.Owner.Window.GetPos()
+		ownerW, ownerH = opt.Owner.Window.GetSize()
+		m = monitors[max(0, min(monitorAt(ownerX+ownerW/2, ownerY+ownerH/2)-1, len(monitors)-1))]
+	}
 	win.Gd.ScaleX, win.Gd.ScaleY = m.GetContentScale()
 	if NoScaling {
 		win.Gd.ScaleX, win.Gd.ScaleY = 1.0, 1.0
@@ -117,7 +207,24 @@ func CreateWindow(x, y, w, h int, name string, monitorNo int, userScale float32)
 	if y < 0 {
 		PosY = PosY + (SizePxY-h)/2
 	}
-	win.Window = createInvisibleWindow(w, h, name, nil)
+	if opt.Owner != nil {
+		PosX, PosY = ownerX+max(0, x), ownerY+max(0, y)
+		if x < 0 {
+			PosX = ownerX + (ownerW-w)/2
+		}
+		if y < 0 {
+			PosY = ownerY + (ownerH-h)/2
+		}
+		x, y = 0, 0
+	}
+	// Share textures with the other windows
+	var share *GlfwWindow
+	WinListMutex.RLock()
+	if len(WindowList) > 0 {
+		share = WindowList[0].Window
+	}
+	WinListMutex.RUnlock()
+	win.Window = createInvisibleWindow(w, h, name, nil, share)
 	win.Gd.ScaleX, win.Gd.ScaleY = win.Window.GetContentScale()
 	win.LeftBtnUpTime = time.Now()
 	lb, tb, rb, bb := win.Window.GetFrameSize()
@@ -138,10 +245,16 @@ func CreateWindow(x, y, w, h int, name string, monitorNo int, userScale float32)
 	win.Wno = wno
 	WindowCount.Add(1)
 	win.Name = name
+	g, restored := LoadGeometry(name)
+	if restored {
+		win.restoreGeometry(g)
+	}
 	win.Trigger = make(chan bool, 1)
-	setupCursors()
 	setCallbacks(win.Window)
 	win.Window.Show()
+	if restored && g.Maximized {
+		win.Window.Maximize()
+	}
 	slog.Debug("CreateWindow()",
 		"ScaleX", f32.F2S(win.Gd.ScaleX, 2), ""+
 			"ScaleY", f32.F2S(win.Gd.ScaleY, 2),
@@ -153,20 +266,18 @@ func CreateWindow(x, y, w, h int, name string, monitorNo int, userScale float32)
 	win.Window.Focus()
 	LoadOpenGl(win)
 	win.ClearMouseBtns()
+	if wno == 0 {
+		win.startRecordOrPlay()
+	}
 	slog.Debug("CreateWindow() done", "Name", name)
 	return win
 }
 
+// BlinkFrequency is the number of text cursor blinks pr second.
 var BlinkFrequency = 2
-var BlinkState atomic.Bool
 
-func Blinker() {
-	for {
-		time.Sleep(time.Second / time.Duration(BlinkFrequency*2))
-		BlinkState.Store(!BlinkState.Load())
-		Invalidate()
-	}
-}
+// BlinkState is true when the text cursor is visible.
+var BlinkState atomic.Bool
 
 // Init will initialize the system.
 // It should be called once, at the very beginning of the application.
@@ -180,11 +291,21 @@ func Init() {
 	} else {
 		MinFrameDelay = time.Second / time.Duration(*maxFps)
 	}
+	theme.SetDefaultPalette(true)
+	Headless = Headless || *headlessFlag
+	if Headless {
+		// Use a window-less OpenGL context, and no monitors
+		if err := initHeadless(); err != nil {
+			panic("Headless initialization failed: " + err.Error())
+		}
+		Monitors = nil
+		OpenGlStarted = true
+		return
+	}
 	// Initialize glfw
 	if err := glfwInit(); err != nil {
 		panic(err)
 	}
-	theme.SetDefaultPalette(true)
 	SetDefaultHints()
 	// Check all monitors and print size data
 	Monitors = GetMonitors()
@@ -198,7 +319,6 @@ func Init() {
 			"WidthPx", SizePxX, "HeightPx", SizePxY, "PosX", PosX, "PosY", PosY,
 			"ScaleX", f32.F2S(mScaleX, 3), "ScaleY", f32.F2S(mScaleY, 3))
 	}
-	go Blinker()
 }
 
 func (win *Window) UpdateSizeDp() {
@@ -208,7 +328,7 @@ func (win *Window) UpdateSizeDp() {
 		win.WidthDp = float32(win.WidthPx)
 		win.HeightDp = float32(win.HeightPx)
 	} else {
-		win.Gd.ScaleX, win.Gd.ScaleY = win.Window.GetContentScale()
+		win.Gd.ScaleX, win.Gd.ScaleY = win.contentScale()
 		win.Gd.ScaleX *= win.UserScale
 		win.Gd.ScaleY *= win.UserScale
 		win.WidthDp = float32(win.WidthPx) / win.Gd.ScaleX
@@ -237,12 +357,19 @@ func LoadOpenGl(w *Window) {
 		slog.Debug("OpenGL", "version", version)
 	}
 	w.Gd.InitGpu()
-	font.LoadDefaultFonts(font.DefaultDpi * w.Gd.ScaleX)
-	gpu.LoadIcons()
-	DetachCurrentContext()
+	if WindowCount.Load() == 1 {
+		// Other windows share the textures with the first one. Glyphs for
+		// other dpi values are made by DrawText when they are needed.
+		font.LoadDefaultFonts(font.DefaultDpi * w.Gd.ScaleX)
+		gpu.LoadIcons()
+	}
+	w.DetachContext()
 }
 
 func GetCurrentWindow() *Window {
+	if Headless {
+		return currentWindow
+	}
 	return GetWindow(GetCurrentContext())
 }
 
@@ -259,6 +386,21 @@ func (win *Window) Defer(f func()) {
 	win.DeferredFunctions = append(win.DeferredFunctions, f)
 }
 
+// HandleUnusedKeys registers f to be called from EndFrame, after the focus is moved
+// by the arrow keys. Functions registered first are called first. Then KeyEvents and
+// TakeKey give only the keys that are not used by the widgets or by the focus navigation.
+// The shortcuts get the keys left after these functions.
+func (win *Window) HandleUnusedKeys(f func()) {
+	win.unusedKeyHandlers = append(win.unusedKeyHandlers, f)
+}
+
+func (win *Window) runUnusedKeyHandlers() {
+	for _, f := range win.unusedKeyHandlers {
+		f()
+	}
+	win.unusedKeyHandlers = win.unusedKeyHandlers[:0]
+}
+
 func (win *Window) RunDeferred() {
 	for _, f := range win.DeferredFunctions {
 		f()
@@ -267,11 +409,60 @@ func (win *Window) RunDeferred() {
 }
 
 func (win *Window) MakeContextCurrent() {
+	if win.offscreen != nil {
+		if err := headlessMakeCurrent(); err != nil {
+			slog.Error("MakeContextCurrent failed", "Error", err)
+		}
+		currentWindow = win
+		win.offscreen.bind(win.WidthPx, win.HeightPx)
+		return
+	}
 	win.Window.MakeContextCurrent()
 }
 
-func (win *Window) SetCursor(c int) {
-	win.Cursor = c
+// DetachContext releases the OpenGL context from the current thread.
+func (win *Window) DetachContext() {
+	if win.offscreen != nil {
+		currentWindow = nil
+		headlessDetach()
+		return
+	}
+	DetachCurrentContext()
+}
+
+// ShouldClose is true when the window is about to be closed.
+func (win *Window) ShouldClose() bool {
+	if win.offscreen != nil {
+		return win.offscreen.shouldClose
+	}
+	return win.Window.ShouldClose()
+}
+
+// SetShouldClose marks the window for closing. It is destroyed by Running().
+func (win *Window) SetShouldClose(close bool) {
+	if win.offscreen != nil {
+		win.offscreen.shouldClose = close
+	} else {
+		win.Window.SetShouldClose(close)
+	}
+	// Wake the event loop, so the window is closed also when the application is idle
+	wakeup()
+}
+
+// contentScale returns the scaling of the monitor where the window is placed.
+func (win *Window) contentScale() (float32, float32) {
+	if win.offscreen != nil {
+		return HeadlessScale, HeadlessScale
+	}
+	return win.Window.GetContentScale()
+}
+
+// cursorPos returns the mouse position in pixels.
+func (win *Window) cursorPos() (float64, float64) {
+	if win.offscreen != nil {
+		return float64(win.mousePos.X * win.Gd.ScaleX), float64(win.mousePos.Y * win.Gd.ScaleY)
+	}
+	return win.Window.GetCursorPos()
 }
 
 // Invalidate will trigger all windows to paint their contents
@@ -285,8 +476,12 @@ func Invalidate() {
 
 func Running() bool {
 	for wno, win := range WindowList {
-		if win.Window.ShouldClose() {
-			win.Window.Destroy()
+		if win.ShouldClose() {
+			win.saveGeometry()
+			win.stopRecording()
+			win.closeOwned()
+			win.Destroy()
+			win.mirrorKeys(false)
 			WinListMutex.Lock()
 			WindowList = append(WindowList[:wno], WindowList[wno+1:]...)
 			WinListMutex.Unlock()
@@ -311,7 +506,7 @@ func AbortAfter(delay time.Duration, windowCount int) {
 	time.Sleep(delay)
 	// Close all windows
 	for _, w := range WindowList {
-		w.Window.SetShouldClose(true)
+		w.SetShouldClose(true)
 	}
 }
 
@@ -327,6 +522,9 @@ func Capture(win *Window, x, y, w, h int) *image.RGBA {
 	h = int(float32(h) * win.Gd.ScaleY)
 	y = win.HeightPx - h - y
 	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	if win.offscreen != nil {
+		win.offscreen.resolve()
+	}
 	gl.PixelStorei(gl.PACK_ALIGNMENT, 1)
 	gl.ReadPixels(int32(x), int32(y), int32(w), int32(h),
 		gl.RGBA, gl.UNSIGNED_BYTE, unsafe.Pointer(&img.Pix[0]))
@@ -354,18 +552,41 @@ func Capture(win *Window, x, y, w, h int) *image.RGBA {
 func (win *Window) ClearMouseBtns() {
 	win.LeftBtnIsDown = false
 	win.Dragging = false
+	win.dragTag = nil
 	win.ScrolledDistY = 0.0
+	win.ScrolledDistX = 0.0
 	win.LeftBtnDoubleClicked = false
 	win.LeftBtnClicked = false
 	win.LeftBtnUpTime = time.Time{}
+	win.RightBtnIsDown = false
 	win.RightBtnDoubleClicked = false
 	win.RightBtnClicked = false
 	win.RightBtnUpTime = time.Time{}
+	win.MiddleBtnIsDown = false
+	win.MiddleBtnDoubleClicked = false
+	win.MiddleBtnClicked = false
+	win.MiddleBtnUpTime = time.Time{}
+}
+
+// endDrag stops dragging when the button that started the drag is released
+func (win *Window) endDrag(button MouseButton) {
+	if win.DragButton == button {
+		win.Dragging = false
+		win.dragTag = nil
+	}
+}
+
+// btnPress stores the position and time used for long-press detection
+func (win *Window) btnPress(button MouseButton) {
+	win.btnDownPos[button] = win.mousePos
+	win.longPressed[button] = false
+	// Make sure a frame is drawn when the long-press time has elapsed
+	AfterFunc(LongPressTime, win.Invalidate)
 }
 
 func (win *Window) leftBtnRelease() {
 	win.LeftBtnIsDown = false
-	win.Dragging = false
+	win.endDrag(MouseButtonLeft)
 	if time.Since(win.LeftBtnUpTime) < DoubleClickTime {
 		// slog.Debug("	MouseCb: - DoubleClick:")
 		win.LeftBtnDoubleClicked = true
@@ -380,10 +601,12 @@ func (win *Window) leftBtnPress() {
 	win.LeftBtnIsDown = true
 	win.LeftBtnClicked = false
 	win.LeftBtnDownTime = time.Now()
+	win.btnPress(MouseButtonLeft)
 }
 
 func (win *Window) rightBtnRelease() {
 	win.RightBtnIsDown = false
+	win.endDrag(MouseButtonRight)
 	if time.Since(win.RightBtnUpTime) < DoubleClickTime {
 		// slog.Debug("MouseCb: - Right DoubleClick:")
 		win.RightBtnDoubleClicked = true
@@ -393,10 +616,30 @@ func (win *Window) rightBtnRelease() {
 	}
 	win.RightBtnUpTime = time.Now()
 }
+
 func (win *Window) rightBtnPress() {
 	win.RightBtnIsDown = true
 	win.RightBtnClicked = false
 	win.RightBtnDownTime = time.Now()
+	win.btnPress(MouseButtonRight)
+}
+
+func (win *Window) middleBtnRelease() {
+	win.MiddleBtnIsDown = false
+	win.endDrag(MouseButtonMiddle)
+	if time.Since(win.MiddleBtnUpTime) < DoubleClickTime {
+		win.MiddleBtnDoubleClicked = true
+	} else {
+		win.MiddleBtnClicked = true
+	}
+	win.MiddleBtnUpTime = time.Now()
+}
+
+func (win *Window) middleBtnPress() {
+	win.MiddleBtnIsDown = true
+	win.MiddleBtnClicked = false
+	win.MiddleBtnDownTime = time.Now()
+	win.btnPress(MouseButtonMiddle)
 }
 
 func (win *Window) SimPos(x, y float32) {
@@ -428,6 +671,54 @@ func (win *Window) SimLeftBtnRelease(x, y float32) {
 	win.leftBtnRelease()
 }
 
+func (win *Window) SimRightBtnPress(x, y float32) {
+	win.mousePos.X = x
+	win.mousePos.Y = y
+	win.rightBtnPress()
+}
+
+func (win *Window) SimRightBtnRelease(x, y float32) {
+	win.mousePos.X = x
+	win.mousePos.Y = y
+	win.rightBtnRelease()
+}
+
+func (win *Window) SimRightClick(x, y float32) {
+	win.mousePos.X = x
+	win.mousePos.Y = y
+	win.RightBtnClicked = true
+}
+
+func (win *Window) SimRightDoubleClick(x, y float32) {
+	win.mousePos.X = x
+	win.mousePos.Y = y
+	win.RightBtnDoubleClicked = true
+}
+
+func (win *Window) SimMiddleBtnPress(x, y float32) {
+	win.mousePos.X = x
+	win.mousePos.Y = y
+	win.middleBtnPress()
+}
+
+func (win *Window) SimMiddleBtnRelease(x, y float32) {
+	win.mousePos.X = x
+	win.mousePos.Y = y
+	win.middleBtnRelease()
+}
+
+func (win *Window) SimMiddleClick(x, y float32) {
+	win.mousePos.X = x
+	win.mousePos.Y = y
+	win.MiddleBtnClicked = true
+}
+
+func (win *Window) SimMiddleDoubleClick(x, y float32) {
+	win.mousePos.X = x
+	win.mousePos.Y = y
+	win.MiddleBtnDoubleClicked = true
+}
+
 // UpdateResolution sets the resolution for all programs
 func (win *Window) UpdateResolution() {
 	ww := int32(win.WidthPx)
@@ -446,11 +737,20 @@ func (win *Window) Fps() float64 {
 }
 
 func (win *Window) Destroy() {
+	if OnAccessTreeChange != nil {
+		OnAccessTreeChange(win, nil)
+	}
+	if win.offscreen != nil {
+		win.MakeContextCurrent()
+		win.offscreen.delete()
+		win.DetachContext()
+		return
+	}
 	win.Window.Destroy()
 }
 
 func (win *Window) Invalidate() {
-	PostEmptyEvent()
+	wakeup()
 }
 
 func PollEvents() {
@@ -460,75 +760,180 @@ func PollEvents() {
 		// Sleep the remaining time
 		time.Sleep(MinFrameDelay - timeUsed)
 	}
-	// Then wait for an event. Make sure we wait a positive interval at least 10nS
-	WaitEventsTimeout(max(1e-8, float32(MaxFrameDelay-MinFrameDelay)/1e9))
+	// Wait no longer than to the first timer, and not at all when animating
+	maxWait := time.Hour
+	if MaxFrameDelay > 0 {
+		maxWait = MaxFrameDelay - MinFrameDelay
+	}
+	wait := nextWakeup(maxWait)
+	if Headless {
+		// There are no events from a display, only wait for Invalidate()
+		select {
+		case <-headlessWakeup:
+		case <-time.After(wait):
+		}
+	} else {
+		// Then wait for an event. Make sure we wait a positive interval at least 10nS
+		WaitEventsTimeout(max(1e-8, float32(wait)/1e9))
+		pollMonitors()
+	}
 	LastPollTime = time.Now()
+	runOnMain()
+	runTimers()
 }
 
 func Shutdown() {
 	WinListMutex.Lock()
 	for _, win := range WindowList {
-		win.Window.Destroy()
+		win.saveGeometry()
+		win.stopRecording()
+		win.Destroy()
 	}
 	WindowList = WindowList[0:0]
 	WindowCount.Store(0)
 	WinListMutex.Unlock()
-	Terminate()
+	if Headless {
+		terminateHeadless()
+	} else {
+		Terminate()
+		releaseCursors()
+	}
 	OpenGlStarted = false
+	clearTimers()
+	clearOnMain()
 }
 
 func (win *Window) HandleFocus(focused bool) {
+	win.record(RecordedEvent{Type: "focus", Focused: focused})
+	win.SimFocus(focused)
+	win.Invalidate()
+	if m := win.ModalChild(); focused && m != nil {
+		// Input goes to the modal window, so move the focus there.
+		m.focus()
+	}
+}
+
+// SimFocus simulates the window getting or losing focus.
+func (win *Window) SimFocus(focused bool) {
 	win.Focused = focused
 	if !focused {
 		slog.Debug("Lost focus", "Wno", win.Wno+1)
+		// Keys released in other windows are not reported
+		win.keyDown = [len(win.keyDown)]bool{}
 	} else {
 		slog.Debug("Got focus", "Wno", win.Wno+1)
 	}
+	win.mirrorKeys(focused)
 	win.ClearMouseBtns()
-	win.Invalidate()
 }
 
 func (win *Window) HandleKey(key Key, scancode int, action Action, mods ModifierKey) {
 	// slog.Debug("keyCallback", "key", key, "scancode", scancode, "action", action, "mods", mods)
+	win.record(RecordedEvent{Type: "key", Key: key, Scancode: scancode, Action: action, Mods: mods})
 	win.Invalidate()
-	if key == KeyTab && action == Release {
+	win.pushEvent(Event{Kind: KeyEvent, Key: key, Scancode: scancode, Action: action, Mods: mods, Char: keyChar(key, scancode)})
+	win.handleKey(key, action, mods)
+}
+
+// SimKeyEvent simulates a single key event (press, release or repeat) in the current frame.
+func (win *Window) SimKeyEvent(key Key, scancode int, action Action, mods ModifierKey) {
+	win.events = append(win.events, Event{Kind: KeyEvent, Key: key, Scancode: scancode, Action: action, Mods: mods, Char: keyChar(key, scancode)})
+	win.handleKey(key, action, mods)
+}
+
+func (win *Window) handleKey(key Key, action Action, mods ModifierKey) {
+	// Ctrl+Tab is handled in navigate, so that widgets like wid.Tabs can use it first
+	if key == KeyTab && action == Release && mods&ModControl == 0 {
 		win.MoveByKey(mods != ModShift)
 	}
 	if action == Release || action == Repeat {
 		win.LastKey = key
 	}
-	if action == Press {
-		KeyIsDown[key] = true
-	} else if action == Release {
-		KeyIsDown[key] = false
-	}
+	win.setKeyDown(key, action)
 	win.LastMods = mods
 }
 
 func (win *Window) HandleMouseButton(button MouseButton, action Action, mods ModifierKey) {
 	win.LastMods = mods
-	x, y := win.Window.GetCursorPos()
+	x, y := win.cursorPos()
 	win.mousePos.X = float32(x) / win.Gd.ScaleX
 	win.mousePos.Y = float32(y) / win.Gd.ScaleY
 	// slog.Debug("MouseCb:", "Button", button, "X", x, "Y", y, "Action", action, "FromWindow", win.Wno, "Pos", win.mousePos)
-	if button == MouseButtonLeft {
+	win.record(RecordedEvent{Type: "button", Button: button, Action: action, Mods: mods, X: win.mousePos.X, Y: win.mousePos.Y})
+	win.pushEvent(Event{Kind: MouseBtnEvent, Button: button, Action: action, Mods: mods, Pos: win.mousePos})
+	win.handleMouseButton(button, action)
+	win.Invalidate()
+}
+
+// SimMouseButton simulates a mouse button event at the given position, in the current frame.
+func (win *Window) SimMouseButton(button MouseButton, action Action, mods ModifierKey, x, y float32) {
+	win.LastMods = mods
+	win.events = append(win.events, Event{Kind: MouseBtnEvent, Button: button, Action: action, Mods: mods, Pos: f32.Pos{X: x, Y: y}})
+	switch {
+	case button == MouseButtonLeft && action == Press:
+		win.SimLeftBtnPress(x, y)
+	case button == MouseButtonLeft && action == Release:
+		win.SimLeftBtnRelease(x, y)
+	case button == MouseButtonRight && action == Press:
+		win.SimRightBtnPress(x, y)
+	case button == MouseButtonRight && action == Release:
+		win.SimRightBtnRelease(x, y)
+	case button == MouseButtonMiddle && action == Press:
+		win.SimMiddleBtnPress(x, y)
+	case button == MouseButtonMiddle && action == Release:
+		win.SimMiddleBtnRelease(x, y)
+	default:
+		win.SimPos(x, y)
+	}
+}
+
+func (win *Window) handleMouseButton(button MouseButton, action Action) {
+	switch button {
+	case MouseButtonLeft:
 		if action == Release {
 			win.leftBtnRelease()
 		} else if action == Press {
 			win.leftBtnPress()
 		}
-		win.Invalidate()
+	case MouseButtonRight:
+		if action == Release {
+			win.rightBtnRelease()
+		} else if action == Press {
+			win.rightBtnPress()
+		}
+	case MouseButtonMiddle:
+		if action == Release {
+			win.middleBtnRelease()
+		} else if action == Press {
+			win.middleBtnPress()
+		}
 	}
 }
 
 func (win *Window) HandleMousePos(xPos float64, yPos float64) {
 	win.mousePos.X = float32(xPos) / win.Gd.ScaleX
 	win.mousePos.Y = float32(yPos) / win.Gd.ScaleY
+	win.record(RecordedEvent{Type: "pos", X: win.mousePos.X, Y: win.mousePos.Y})
+	win.pushEvent(Event{Kind: MouseMoveEvent, Pos: win.mousePos})
 	win.Invalidate()
 }
 
 func (win *Window) HandleMouseScroll(xOff float64, yOff float64) {
 	// slog.Debug("ScrollCb:", "dx", xOff, "dy", yOff)
+	win.record(RecordedEvent{Type: "scroll", Dx: float32(xOff), Dy: float32(yOff), Mods: win.LastMods})
+	win.pushEvent(Event{Kind: ScrollEvent, Dx: float32(xOff), Dy: float32(yOff), Mods: win.LastMods, Pos: win.mousePos})
+	win.handleMouseScroll(xOff, yOff)
+	win.Invalidate()
+}
+
+// SimScroll simulates the mouse wheel in the current frame, with the given modifier keys down.
+func (win *Window) SimScroll(xOff, yOff float32, mods ModifierKey) {
+	win.LastMods = mods
+	win.events = append(win.events, Event{Kind: ScrollEvent, Dx: xOff, Dy: yOff, Mods: mods, Pos: win.mousePos})
+	win.handleMouseScroll(float64(xOff), float64(yOff))
+}
+
+func (win *Window) handleMouseScroll(xOff float64, yOff float64) {
 	if win.LastMods == ModControl {
 		// ctrl + scroll-wheel will zoom the whole window by changing gpu.UserScale.
 		if yOff > 0 {
@@ -537,14 +942,19 @@ func (win *Window) HandleMouseScroll(xOff float64, yOff float64) {
 			win.UserScale /= ZoomFactor
 		}
 		win.UpdateSizeDp()
+	} else if win.LastMods == ModShift {
+		// shift + scroll-wheel will scroll horizontally
+		win.ScrolledDistX = float32(yOff)
 	} else {
 		win.ScrolledDistY = float32(yOff)
+		win.ScrolledDistX = float32(xOff)
 	}
-	win.Invalidate()
 }
 
 func (win *Window) HandleChar(char rune) {
 	slog.Debug("charCallback()", "Rune", int(char))
+	win.record(RecordedEvent{Type: "char", Rune: char})
 	win.Invalidate()
+	win.pushEvent(Event{Kind: CharEvent, Rune: char})
 	win.LastRune = char
 }
@@ -0,0 +1,250 @@
+package test
+
+import (
+	"bufio"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/jkvatne/jkvgui/atspi"
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/wid"
+)
+
+var (
+	accessName  = "Ole"
+	accessCheck = true
+	accessRadio = "B"
+)
+
+func accessForm() wid.Wid {
+	return wid.Col(nil,
+		wid.Label("Person", nil),
+		wid.Edit(&accessName, "Name", nil, nil),
+		wid.Checkbox("Married", &accessCheck, nil, nil, ""),
+		wid.RadioButton("Option B", &accessRadio, "B", nil),
+		wid.Btn("", nil, func() {}, nil, "Save the data"),
+	)
+}
+
+func TestAccessTree(t *testing.T) {
+	slog.Info("TestAccessTree")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 400, 300, "Test", 1, 1.0)
+	w.Focused = true
+	w.SetFocusedTag(&accessName)
+	changes := 0
+	sys.OnAccessTreeChange = func(win *sys.Window, tree *sys.AccessTree) { changes++ }
+	defer func() { sys.OnAccessTreeChange = nil }()
+	for range 2 {
+		w.StartFrame()
+		wid.Show(accessForm())
+		w.EndFrame()
+	}
+	if changes != 1 {
+		t.Errorf("Expected one change of the tree, got %d", changes)
+	}
+	root := w.AccessTree().Root
+	if root.Role != sys.RoleWindow || root.Name != "Test" || len(root.Children) != 5 {
+		t.Fatalf("Wrong root node %+v", root)
+	}
+	expected := []struct {
+		role  sys.Role
+		name  string
+		value string
+		state sys.AccessState
+	}{
+		{sys.RoleLabel, "Person", "", 0},
+		{sys.RoleEdit, "Name", "Ole", sys.StateFocusable | sys.StateEditable | sys.StateFocused},
+		{sys.RoleCheckBox, "Married", "", sys.StateFocusable | sys.StateChecked},
+		{sys.RoleRadioButton, "Option B", "", sys.StateFocusable | sys.StateChecked},
+		{sys.RoleButton, "Save the data", "", sys.StateFocusable},
+	}
+	for i, e := range expected {
+		n := root.Children[i]
+		if n.Role != e.role || n.Name != e.name || n.Value != e.value || n.State != e.state {
+			t.Errorf("Node %d: expected %s %q %q %b, got %s %q %q %b", i, e.role, e.name, e.value, e.state, n.Role, n.Name, n.Value, n.State)
+		}
+		if n.Bounds.W <= 0 || n.Bounds.H <= 0 || n.Bounds.X < 0 {
+			t.Errorf("Node %d has wrong bounds %v", i, n.Bounds)
+		}
+	}
+
+	accessCheck = false
+	w.StartFrame()
+	wid.Show(accessForm())
+	w.EndFrame()
+	accessCheck = true
+	if changes != 2 || w.AccessTree().Root.Children[2].State&sys.StateChecked != 0 {
+		t.Errorf("Unchecking the checkbox should change the tree")
+	}
+}
+
+// startBus runs a private D-Bus daemon, and returns its address.
+func startBus(t *testing.T) string {
+	path, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon not found")
+	}
+	cmd := exec.Command(path, "--session", "--nofork", "--print-address=1")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = cmd.Start(); err != nil {
+		t.Skip("Could not start dbus-daemon: ", err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+	addr, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Skip("dbus-daemon gave no address: ", err)
+	}
+	return strings.TrimSpace(addr)
+}
+
+func TestAtspiBridge(t *testing.T) {
+	slog.Info("TestAtspiBridge")
+	addr := startBus(t)
+	conn, err := dbus.Connect(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := dbus.Connect(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	bridge, err := atspi.StartOn(conn, "TestApp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bridge.Close()
+	w := sys.CreateWindow(0, 0, 400, 300, "Test", 1, 1.0)
+	w.StartFrame()
+	wid.Show(accessForm())
+	w.EndFrame()
+
+	name := conn.Names()[0]
+	app := client.Object(name, "/org/a11y/atspi/accessible/root")
+	var windows []atspi.Ref
+	if err = app.Call("org.a11y.atspi.Accessible.GetChildren", 0).Store(&windows); err != nil || len(windows) != 1 {
+		t.Fatalf("Expected one window, got %v, %v", windows, err)
+	}
+	v, err := app.GetProperty("org.a11y.atspi.Accessible.Name")
+	if err != nil || v.Value() != "TestApp" {
+		t.Errorf("Wrong application name %v, %v", v, err)
+	}
+
+	var widgets []atspi.Ref
+	win := client.Object(name, windows[0].Path)
+	if err = win.Call("org.a11y.atspi.Accessible.GetChildren", 0).Store(&widgets); err != nil || len(widgets) != 5 {
+		t.Fatalf("Expected 5 widgets, got %v, %v", widgets, err)
+	}
+	edit := client.Object(name, widgets[1].Path)
+	var role uint32
+	if err = edit.Call("org.a11y.atspi.Accessible.GetRole", 0).Store(&role); err != nil || role != 79 {
+		t.Errorf("Expected role entry (79), got %d, %v", role, err)
+	}
+	var text string
+	if err = edit.Call("org.a11y.atspi.Text.GetText", 0, int32(0), int32(-1)).Store(&text); err != nil || text != "Ole" {
+		t.Errorf("Expected text Ole, got %q, %v", text, err)
+	}
+	var states []uint32
+	cb := client.Object(name, widgets[2].Path)
+	if err = cb.Call("org.a11y.atspi.Accessible.GetState", 0).Store(&states); err != nil || len(states) != 2 || states[0]&(1<<4) == 0 {
+		t.Errorf("Checkbox should be checked, got %v, %v", states, err)
+	}
+	v, err = client.Object(name, widgets[4].Path).GetProperty("org.a11y.atspi.Accessible.Name")
+	if err != nil || v.Value() != "Save the data" {
+		t.Errorf("Wrong button name %v, %v", v, err)
+	}
+	var r struct{ X, Y, W, H int32 }
+	if err = cb.Call("org.a11y.atspi.Component.GetExtents", 0, uint32(1)).Store(&r); err != nil || r.W <= 0 || r.H <= 0 || r.Y <= 0 {
+		t.Errorf("Wrong extents %v, %v", r, err)
+	}
+}
+
+func TestAtspiMenu(t *testing.T) {
+	slog.Info("TestAtspiMenu")
+	addr := startBus(t)
+	conn, err := dbus.Connect(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := dbus.Connect(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	bridge, err := atspi.StartOn(conn, "TestApp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bridge.Close()
+	w := sys.CreateWindow(0, 0, 400, 300, "Test", 1, 1.0)
+	menus := []wid.MenuItem{
+		{Text: "File", Items: []wid.MenuItem{{Text: "New"}}},
+		{Text: "Edit", Items: []wid.MenuItem{{Text: "Cut"}}},
+	}
+	w.StartFrame()
+	wid.Show(wid.Col(nil, wid.MenuBar(&wid.MenuState{}, menus, nil)))
+	w.EndFrame()
+
+	name := conn.Names()[0]
+	var windows, widgets, items []atspi.Ref
+	if err = client.Object(name, "/org/a11y/atspi/accessible/root").Call("org.a11y.atspi.Accessible.GetChildren", 0).Store(&windows); err != nil || len(windows) != 1 {
+		t.Fatalf("Expected one window, got %v, %v", windows, err)
+	}
+	if err = client.Object(name, windows[0].Path).Call("org.a11y.atspi.Accessible.GetChildren", 0).Store(&widgets); err != nil || len(widgets) != 1 {
+		t.Fatalf("Expected the menu bar, got %v, %v", widgets, err)
+	}
+	bar := client.Object(name, widgets[0].Path)
+	v, err := bar.GetProperty("org.a11y.atspi.Accessible.ChildCount")
+	if err != nil || v.Value() != int32(2) {
+		t.Errorf("Expected 2 menus in the bar, got %v, %v", v, err)
+	}
+	if err = bar.Call("org.a11y.atspi.Accessible.GetChildren", 0).Store(&items); err != nil || len(items) != 2 {
+		t.Fatalf("Expected 2 menus, got %v, %v", items, err)
+	}
+	if items[1].Path != widgets[0].Path+"/n1" {
+		t.Errorf("Expected a nested path, got %s", items[1].Path)
+	}
+	edit := client.Object(name, items[1].Path)
+	v, err = edit.GetProperty("org.a11y.atspi.Accessible.Name")
+	if err != nil || v.Value() != "Edit" {
+		t.Errorf("Wrong menu name %v, %v", v, err)
+	}
+	var index int32
+	if err = edit.Call("org.a11y.atspi.Accessible.GetIndexInParent", 0).Store(&index); err != nil || index != 1 {
+		t.Errorf("Expected index 1, got %d, %v", index, err)
+	}
+	v, err = edit.GetProperty("org.a11y.atspi.Accessible.Parent")
+	if err != nil || v.Value().([]any)[1] != widgets[0].Path {
+		t.Errorf("Expected the menu bar as parent, got %v, %v", v, err)
+	}
+	var child atspi.Ref
+	if err = bar.Call("org.a11y.atspi.Accessible.GetChildAtIndex", 0, int32(0)).Store(&child); err != nil || child.Path != items[0].Path {
+		t.Errorf("Expected the File menu, got %v, %v", child, err)
+	}
+	if err = client.Object(name, widgets[0].Path+"/n5").Call("org.a11y.atspi.Accessible.GetRole", 0).Store(new(uint32)); err == nil {
+		t.Errorf("Expected an error for a missing node")
+	}
+}
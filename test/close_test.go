@@ -0,0 +1,42 @@
+package test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/jkvatne/jkvgui/sys"
+)
+
+func TestCloseRequest(t *testing.T) {
+	slog.Info("TestCloseRequest")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 200, 100, "Test", 1, 1.0)
+	asked := 0
+	allow := false
+	w.OnCloseRequest = func() bool {
+		asked++
+		return allow
+	}
+	w.HandleClose()
+	w.Close()
+	if asked != 2 || w.ShouldClose() || !sys.Running() {
+		t.Errorf("Close should be cancelled by OnCloseRequest, asked %d times", asked)
+	}
+	// A modal window blocks closing of the owner
+	allow = true
+	child := sys.CreateWindow(-1, -1, 100, 50, "Modal", 1, 0, sys.WindowOptions{Owner: w, Modal: true})
+	w.HandleClose()
+	if asked != 2 || w.ShouldClose() {
+		t.Errorf("Owner should not close while a modal window is open")
+	}
+	child.Close()
+	w.Close()
+	if asked != 3 || !w.ShouldClose() {
+		t.Errorf("Close should be allowed by OnCloseRequest")
+	}
+	for sys.Running() {
+	}
+}
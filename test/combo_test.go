@@ -50,6 +50,7 @@ func TestCombo(t *testing.T) {
 	sys.Init()
 	defer sys.Shutdown()
 	sys.NoScaling = false
+	referenceScale(t)
 	win := sys.CreateWindow(0, 0, 200, 200, "Test", 0, 1.0)
 	win.StartFrame()
 	styles := make([]wid.ComboStyle, len(cases))
@@ -75,6 +76,7 @@ func TestComboDoubleClick(t *testing.T) {
 	sys.Init()
 	defer sys.Shutdown()
 	sys.NoScaling = false
+	referenceScale(t)
 	win := sys.CreateWindow(0, 0, 200, 75, "Test", 0, 1.0)
 	win.StartFrame()
 	style := MakeStyle(0)
@@ -95,6 +97,7 @@ func TestComboClick(t *testing.T) {
 	sys.Init()
 	defer sys.Shutdown()
 	sys.NoScaling = false
+	referenceScale(t)
 	win := sys.CreateWindow(0, 0, 200, 100, "Test", 0, 1.0)
 	win.StartFrame()
 	style := MakeStyle(0)
@@ -0,0 +1,49 @@
+package test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/jkvatne/jkvgui/f32"
+	"github.com/jkvatne/jkvgui/gpu"
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/wid"
+)
+
+func TestCursors(t *testing.T) {
+	slog.Info("TestCursors")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 200, 100, "Test", 1, 1.0)
+	w.StartFrame()
+	ctx := wid.NewCtx(w)
+	ctx.SetCursor(sys.HandCursor)
+	if w.Cursor != sys.HandCursor {
+		t.Errorf("Ctx.SetCursor should select the given cursor, got %d", w.Cursor)
+	}
+	w.EndFrame()
+	w.StartFrame()
+	if w.Cursor != sys.ArrowCursor {
+		t.Errorf("The cursor should be reset to the arrow in each frame")
+	}
+	img := gpu.Home.Image(32, f32.Red)
+	if img.Bounds().Dx() != 32 || img.Bounds().Dy() != 32 {
+		t.Errorf("Icon image should be 32x32, got %v", img.Bounds())
+	}
+	if c := img.RGBAAt(10, 20); c.R < 200 || c.G != 0 || c.A < 200 {
+		t.Errorf("Expected a red pixel in the wall of the house, got %v", c)
+	}
+	if c := img.RGBAAt(0, 0); c.A != 0 {
+		t.Errorf("Expected a transparent corner, got %v", c)
+	}
+	w.SetCustomCursor(sys.NewIconCursor(gpu.Home, 32, f32.Black, 16, 16))
+	w.SetCursor(sys.ResizeAllCursor)
+	w.EndFrame()
+	w.SetCursorMode(sys.CursorDisabled)
+	if w.CursorMode() != sys.CursorDisabled {
+		t.Errorf("Cursor mode not set")
+	}
+	w.SetCursorMode(sys.CursorNormal)
+}
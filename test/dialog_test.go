@@ -0,0 +1,89 @@
+package test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/jkvatne/jkvgui/dialog"
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/wid"
+)
+
+func TestModalWindow(t *testing.T) {
+	slog.Info("TestModalWindow")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	owner := sys.CreateWindow(0, 0, 400, 300, "Owner", 1, 1.0)
+	owner.Focused = true
+	drawn := 0
+	var dialogue wid.Wid = func(ctx wid.Ctx) wid.Dim {
+		drawn++
+		if drawn == 2 {
+			dialog.Hide()
+		}
+		return wid.Dim{W: ctx.W, H: ctx.H}
+	}
+	shown := false
+	frame := func() {
+		owner.StartFrame()
+		if !shown {
+			dialog.ShowWindow("Dialogue", &dialogue, 200, 100)
+			shown = true
+		}
+		dialog.Display(owner)
+		owner.EndFrame()
+	}
+	// The window is created by the event loop, and drawn in the next frame of the owner
+	frame()
+	if owner.ModalChild() != nil || drawn != 0 {
+		t.Errorf("The dialogue window should not be created before PollEvents")
+	}
+	sys.PollEvents()
+	win := owner.ModalChild()
+	frame()
+	if win == nil || win.Owner() != owner || !win.Modal() || owner.ModalChild() != win {
+		t.Fatalf("Dialogue window should be a modal window owned by the main window")
+	}
+	if drawn != 1 || !owner.Blocked() || !owner.SuppressEvents {
+		t.Errorf("Owner should be blocked while the dialogue is open")
+	}
+	// Input to the owner is ignored
+	owner.StartFrame()
+	owner.SimKey(sys.KeyTab, 0)
+	if len(owner.KeyEvents()) != 0 || owner.At(&drawn) {
+		t.Errorf("Blocked window should not get input")
+	}
+	owner.EndFrame()
+	// The dialogue closes itself in the second frame
+	frame()
+	if drawn != 2 || !win.ShouldClose() || owner.Blocked() {
+		t.Errorf("Dialogue window was not closed")
+	}
+	if !sys.Running() || len(sys.WindowList) != 1 {
+		t.Errorf("Expected only the owner window left")
+	}
+	frame()
+	if owner.SuppressEvents {
+		t.Errorf("Owner should get input when the dialogue is closed")
+	}
+
+	// Closing the owner will also close owned windows
+	child := sys.CreateWindow(10, 10, 100, 100, "Child", 1, 0, sys.WindowOptions{Owner: owner})
+	if child.UserScale != owner.UserScale || owner.Blocked() {
+		t.Errorf("Owned window should use the owner's scale, and not block it")
+	}
+	// Without an owner, a zero scale is not used
+	free := sys.CreateWindow(10, 10, 100, 100, "Free", 1, 0, sys.WindowOptions{Owner: nil})
+	if free.UserScale != 1 {
+		t.Errorf("Expected scale 1 for a window without owner, got %v", free.UserScale)
+	}
+	free.SetShouldClose(true)
+	owner.SetShouldClose(true)
+	for sys.Running() {
+	}
+	if len(sys.WindowList) != 0 {
+		t.Errorf("Owned window not closed with the owner")
+	}
+}
@@ -0,0 +1,41 @@
+package test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/jkvatne/jkvgui/f32"
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/wid"
+)
+
+func TestDropTarget(t *testing.T) {
+	slog.Info("TestDropTarget")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 400, 200, "Test", 1, 1.0)
+	w.Focused = true
+	var dropped []string
+	target := wid.DropTarget(func(paths []string) { dropped = paths }, nil, wid.Label("Drop files here", nil))
+	w.StartFrame()
+	// Dropped outside the target
+	w.SimDrop(300, 150, []string{"/tmp/a.log"})
+	wid.Display(w, 10, 10, 200, target)
+	if dropped != nil || w.DropPos != (f32.Pos{X: 300, Y: 150}) {
+		t.Errorf("Drop outside target should not be handled")
+	}
+	w.SimDrop(20, 15, []string{"/tmp/a.log", "/tmp/b.cfg"})
+	wid.Display(w, 10, 10, 200, target)
+	if len(dropped) != 2 || dropped[1] != "/tmp/b.cfg" {
+		t.Errorf("Expected two dropped files, got %v", dropped)
+	}
+	// The drop is only reported once
+	dropped = nil
+	wid.Display(w, 10, 10, 200, target)
+	if dropped != nil {
+		t.Errorf("Drop reported twice")
+	}
+	w.EndFrame()
+}
@@ -82,3 +82,37 @@ func TestEditSelect(t *testing.T) {
 	time.Sleep(time.Millisecond)
 	sys.Shutdown()
 }
+
+func TestEditNumber(t *testing.T) {
+	slog.Info("TestEditNumber")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 600, 70, "Test", 1, 1.0)
+	w.Focused = true
+	n, other := 12, 0
+	form := wid.Edit(&n, "Number", nil, nil)
+	frame := func(input func()) {
+		w.StartFrame()
+		if input != nil {
+			input()
+		}
+		wid.Display(w, 10, 10, 570, form)
+		w.EndFrame()
+	}
+	w.SetFocusedTag(&n)
+	frame(nil)
+	frame(func() { w.SimChar('x') })
+	frame(nil)
+	if e := accessNode(w, sys.RoleEdit, "Number"); e == nil || e.State&sys.StateInvalid == 0 {
+		t.Errorf("Expected the edit to show that the number is not valid")
+	}
+	// The value is kept, and the text reverted, when the focus is lost
+	w.SetFocusedTag(&other)
+	frame(nil)
+	frame(nil)
+	if e := accessNode(w, sys.RoleEdit, "Number"); n != 12 || e == nil || e.Value != "12" || e.State&sys.StateInvalid != 0 {
+		t.Errorf("Expected the invalid text to be reverted, got n=%d", n)
+	}
+}
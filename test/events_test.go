@@ -0,0 +1,39 @@
+package test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/wid"
+)
+
+// TestEventBurst verifies that several keystrokes received before one frame are all handled.
+func TestEventBurst(t *testing.T) {
+	slog.Info("TestEventBurst")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 600, 70, "Test", 1, 1.0)
+	w.Focused = true
+	value := ""
+	entered := false
+	edit := wid.Edit(&value, "Test", func() { entered = true }, nil)
+	w.StartFrame()
+	w.SetFocusedTag(&value)
+	w.SimChar('a')
+	w.SimChar('b')
+	w.SimChar('c')
+	w.SimKey(sys.KeyBackspace, 0)
+	w.SimChar('d')
+	w.SimKey(sys.KeyEnter, 0)
+	wid.Display(w, 10, 10, 570, edit)
+	w.EndFrame()
+	if value != "abd" || !entered {
+		t.Errorf("Expected value abd to be entered, got %q, entered=%v", value, entered)
+	}
+	if len(w.KeyEvents()) != 0 {
+		t.Errorf("Expected all key events to be consumed")
+	}
+}
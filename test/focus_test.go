@@ -0,0 +1,144 @@
+package test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/wid"
+)
+
+// A 3x3 grid of checkboxes, made as a column of rows
+func checkGrid(cb *[9]bool) wid.Wid {
+	var rows []wid.Wid
+	for r := 0; r < 3; r++ {
+		rows = append(rows, wid.Row(nil,
+			wid.Checkbox("A", &cb[r*3], nil, nil, ""),
+			wid.Checkbox("B", &cb[r*3+1], nil, nil, ""),
+			wid.Checkbox("C", &cb[r*3+2], nil, nil, "")))
+	}
+	return wid.Col(nil, rows...)
+}
+
+func TestArrowNavigation(t *testing.T) {
+	slog.Info("TestArrowNavigation")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 300, 200, "Test", 1, 1.0)
+	w.Focused = true
+	var cb [9]bool
+	form := checkGrid(&cb)
+	frame := func(key sys.Key) {
+		w.StartFrame()
+		if key != 0 {
+			w.SimKey(key, 0)
+		}
+		wid.Show(form)
+		w.EndFrame()
+	}
+	w.RequestFocus(&cb[4])
+	frame(0)
+	for _, step := range []struct {
+		key      sys.Key
+		expected int
+	}{
+		{sys.KeyRight, 5}, {sys.KeyRight, 5}, {sys.KeyDown, 8}, {sys.KeyLeft, 7},
+		{sys.KeyUp, 4}, {sys.KeyUp, 1}, {sys.KeyUp, 1}, {sys.KeyLeft, 0},
+	} {
+		frame(step.key)
+		if w.CurrentTag != &cb[step.expected] {
+			t.Errorf("Expected focus on checkbox %d after key %d", step.expected, step.key)
+		}
+	}
+	// Ctrl+Tab moves like Tab when no widget uses it
+	w.StartFrame()
+	w.SimKey(sys.KeyTab, sys.ModControl)
+	wid.Show(form)
+	w.EndFrame()
+	if w.CurrentTag != &cb[1] {
+		t.Errorf("Expected Ctrl+Tab to move the focus to checkbox 1")
+	}
+}
+
+func TestTabOrder(t *testing.T) {
+	slog.Info("TestTabOrder")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 300, 200, "Test", 1, 1.0)
+	w.Focused = true
+	var cb [9]bool
+	var panel [2]bool
+	form := wid.Col(nil,
+		checkGrid(&cb),
+		wid.FocusScope(&panel, wid.Row(nil,
+			wid.Checkbox("X", &panel[0], nil, nil, ""),
+			wid.Checkbox("Y", &panel[1], nil, nil, ""))))
+	frame := func() {
+		w.StartFrame()
+		wid.Show(form)
+		w.EndFrame()
+	}
+	entered, left := 0, 0
+	w.OnFocusChange = func(from, to any) {
+		if to == &cb[8] {
+			entered++
+		}
+		if from == &cb[8] {
+			left++
+		}
+	}
+	// Visit 8 first, then 0, and skip 1..6
+	w.SetTabIndex(&cb[8], 1)
+	for i := 1; i < 7; i++ {
+		w.SetTabIndex(&cb[i], -1)
+	}
+	w.RequestFocus(&cb[0])
+	frame()
+	expected := []int{7, 8, 0, 7}
+	for _, e := range expected {
+		w.MoveByKey(true)
+		frame()
+		if w.CurrentTag != &cb[e] {
+			t.Errorf("Expected Tab to move to checkbox %d", e)
+		}
+		if e == 8 {
+			frame()
+			if !w.FocusEntered(&cb[8]) || w.FocusLeft(&cb[8]) {
+				t.Errorf("FocusEntered not reported")
+			}
+		}
+	}
+	w.MoveByKey(false)
+	frame()
+	if w.CurrentTag != &cb[0] {
+		t.Errorf("Expected Shift+Tab to move back to checkbox 0")
+	}
+	frame()
+	if entered != 1 || left != 1 {
+		t.Errorf("Expected one focus change to and from checkbox 8, got %d and %d", entered, left)
+	}
+
+	// The focus scope is not reached by Tab, but by a request, and Tab then stays inside
+	w.RequestFocusIn(&panel)
+	frame()
+	if w.CurrentTag != &panel[0] {
+		t.Errorf("Expected focus on the first checkbox in the panel")
+	}
+	// The scopes of the focused widget are known when it has been drawn
+	frame()
+	frame()
+	if !w.ScopeEntered(&panel) {
+		t.Errorf("Expected ScopeEntered for the panel")
+	}
+	w.MoveByKey(true)
+	frame()
+	w.MoveByKey(true)
+	frame()
+	if w.CurrentTag != &panel[0] {
+		t.Errorf("Expected Tab to stay inside the panel")
+	}
+}
@@ -0,0 +1,62 @@
+package test
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/jkvatne/jkvgui/sys"
+)
+
+func TestPersistGeometry(t *testing.T) {
+	slog.Info("TestPersistGeometry")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("AppData", t.TempDir())
+	sys.Init()
+	defer sys.Shutdown()
+	if sys.Headless {
+		t.Skip("Window geometry is not used in headless mode")
+	}
+	slog.SetLogLoggerLevel(slog.LevelError)
+	if err := sys.PersistGeometry("jkvgui-test"); err != nil {
+		t.Fatalf("PersistGeometry failed: %v", err)
+	}
+	defer func() { _ = sys.PersistGeometry("") }()
+	w := sys.CreateWindow(0, 0, 300, 200, "Geometry", 1, 1.0)
+	w.UserScale = 1.5
+	if err := w.SaveGeometry(); err != nil {
+		t.Fatalf("SaveGeometry failed: %v", err)
+	}
+	g, ok := sys.LoadGeometry("Geometry")
+	if !ok || g.UserScale != 1.5 || g.Monitor != 1 || g.W <= 0 {
+		t.Errorf("Geometry not saved correctly: %+v", g)
+	}
+	w.SetShouldClose(true)
+	sys.Running()
+
+	// A window with the same name gets the saved zoom
+	w = sys.CreateWindow(0, 0, 100, 100, "Geometry", 1, 1.0)
+	if w.UserScale != 1.5 {
+		t.Errorf("UserScale not restored, got %v", w.UserScale)
+	}
+
+	// A saved geometry that does not fit the monitors is clamped
+	data, _ := json.Marshal(map[string]sys.Geometry{"Huge": {X: -5000, Y: 90000, W: 90000, H: 90000, Monitor: 9, UserScale: 1}})
+	_ = os.WriteFile(sys.GeometryFile(), data, 0o644)
+	w = sys.CreateWindow(0, 0, 100, 100, "Huge", 1, 1.0)
+	x, y := w.Window.GetPos()
+	_, _, mw, mh := sys.Monitors[len(sys.Monitors)-1].GetWorkarea()
+	if w.WidthPx > mw || w.HeightPx > mh || x < -mw || y > 2*mh {
+		t.Errorf("Geometry not clamped to monitor, x=%d, y=%d, w=%d, h=%d", x, y, w.WidthPx, w.HeightPx)
+	}
+
+	// A window only saved while maximized keeps the size it was created with
+	data, _ = json.Marshal(map[string]sys.Geometry{"Max": {Maximized: true, Monitor: 1, UserScale: 1}})
+	_ = os.WriteFile(sys.GeometryFile(), data, 0o644)
+	w = sys.CreateWindow(0, 0, 300, 200, "Max", 1, 1.0)
+	w.Window.Restore()
+	if width, height := w.Window.GetSize(); width < 100 || height < 100 {
+		t.Errorf("Expected the created size after restoring, got %dx%d", width, height)
+	}
+}
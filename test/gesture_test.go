@@ -0,0 +1,122 @@
+package test
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/jkvatne/jkvgui/f32"
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/wid"
+)
+
+func TestGesture(t *testing.T) {
+	slog.Info("TestGesture")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 200, 200, "Test", 1, 1.0)
+	w.Focused = true
+	r := f32.Rect{X: 10, Y: 10, W: 50, H: 50}
+	var tag, other int
+	oldTime := sys.LongPressTime
+	sys.LongPressTime = 10 * time.Millisecond
+	defer func() { sys.LongPressTime = oldTime }()
+	frame := func(f func()) sys.Gesture {
+		w.StartFrame()
+		f()
+		g := w.Gesture(r, &tag)
+		w.EndFrame()
+		return g
+	}
+
+	if g := frame(func() { w.SimPos(100, 100) }); g != (sys.Gesture{}) {
+		t.Errorf("Expected no gesture outside the rectangle, got %+v", g)
+	}
+	if g := frame(func() { w.SimPos(20, 20) }); !g.HoverEnter || !g.Hovered {
+		t.Errorf("Hover enter not detected")
+	}
+	if g := frame(func() {}); g.HoverEnter || !g.Hovered {
+		t.Errorf("Hover enter should be reported once")
+	}
+	if g := frame(func() { w.SimLeftBtnPress(20, 20) }); !g.DragStart || !g.Dragging || g.Delta != (f32.Pos{}) {
+		t.Errorf("Drag start not detected")
+	}
+	if g := frame(func() { w.SimPos(30, 25) }); g.DragStart || !g.Dragging || g.Delta != (f32.Pos{X: 10, Y: 5}) {
+		t.Errorf("Expected drag delta 10,5, got %+v", g)
+	}
+	w.StartFrame()
+	w.SimPos(100, 100)
+	if g := w.Gesture(f32.Rect{X: 90, Y: 90, W: 20, H: 20}, &other); g.Hovered || g.DragStart {
+		t.Errorf("Other widgets should not be hovered while dragging")
+	}
+	g := w.Gesture(r, &tag)
+	w.EndFrame()
+	if !g.HoverLeave || !g.Dragging || g.Delta != (f32.Pos{X: 70, Y: 75}) || g.Offset != (f32.Pos{X: 80, Y: 80}) {
+		t.Errorf("Dragging outside the rectangle failed, got %+v", g)
+	}
+	if g := frame(func() { w.SimLeftBtnRelease(100, 100) }); !g.DragEnd || g.Dragging || g.Offset != (f32.Pos{X: 80, Y: 80}) {
+		t.Errorf("Drag end not detected, got %+v", g)
+	}
+	if g := frame(func() {}); g.DragEnd {
+		t.Errorf("Drag end should be reported once")
+	}
+
+	frame(func() { w.SimLeftBtnPress(20, 20) })
+	time.Sleep(20 * time.Millisecond)
+	if g := frame(func() {}); !g.LongPress {
+		t.Errorf("Long press not detected")
+	}
+	if g := frame(func() {}); g.LongPress {
+		t.Errorf("Long press should be reported once")
+	}
+	frame(func() { w.SimLeftBtnRelease(20, 20) })
+}
+
+func TestResizerDrag(t *testing.T) {
+	slog.Info("TestResizerDrag")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 400, 200, "Test", 1, 1.0)
+	w.Focused = true
+	var state wid.ResizerState
+	var w1, h1 float32
+	left := func(ctx wid.Ctx) wid.Dim {
+		w1 = ctx.W
+		return wid.Dim{W: ctx.W, H: ctx.H}
+	}
+	top := func(ctx wid.Ctx) wid.Dim {
+		h1 = ctx.H
+		return wid.Dim{W: ctx.W, H: ctx.H}
+	}
+	empty := func(ctx wid.Ctx) wid.Dim { return wid.Dim{W: ctx.W, H: ctx.H} }
+	var hstate wid.ResizerState
+	form := wid.VertResizer(&state, nil, left, wid.HorResizer(&hstate, nil, top, empty))
+	frame := func(f func()) {
+		w.StartFrame()
+		f()
+		wid.Display(w, 0, 0, 400, form)
+		w.EndFrame()
+	}
+	frame(func() {})
+	if w1 != 199 {
+		t.Errorf("Expected left width 199, got %0.1f", w1)
+	}
+	frame(func() { w.SimLeftBtnPress(201, 100) })
+	frame(func() { w.SimPos(151, 120) })
+	frame(func() { w.SimLeftBtnRelease(151, 120) })
+	if w1 != 149 {
+		t.Errorf("Expected left width 149 after dragging, got %0.1f", w1)
+	}
+	// The horizontal divider in the right half
+	h0 := h1
+	frame(func() { w.SimLeftBtnPress(300, h0+1) })
+	frame(func() { w.SimPos(300, h0+31) })
+	frame(func() { w.SimLeftBtnRelease(300, h0+31) })
+	if h1 != h0+30 || w1 != 149 {
+		t.Errorf("Expected top height %0.1f and left width 149, got %0.1f and %0.1f", h0+30, h1, w1)
+	}
+}
@@ -0,0 +1,212 @@
+package test
+
+import (
+	"log/slog"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jkvatne/jkvgui/f32"
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/wid"
+)
+
+type gridRow struct {
+	Name string
+	Age  int
+	Ok   bool
+}
+
+// accessNode returns the first node in the window's accessibility tree with the role and name
+func accessNode(w *sys.Window, role sys.Role, name string) *sys.AccessNode {
+	for _, n := range w.AccessTree().Root.Children {
+		if n.Role == role && n.Name == name {
+			return n
+		}
+	}
+	return nil
+}
+
+func TestGrid(t *testing.T) {
+	slog.Info("TestGrid")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 400, 300, "Test", 1, 1.0)
+	w.Focused = true
+	data := make([]gridRow, 200)
+	for i := range data {
+		data[i] = gridRow{Name: "Person " + strconv.Itoa(i), Age: 200 - i}
+	}
+	columns := []wid.GridColumn[gridRow]{
+		{Title: "Name", Get: func(r *gridRow) any { return &r.Name }, Kind: wid.CellEdit},
+		{Title: "Age", Get: func(r *gridRow) any { return &r.Age }, Width: 80},
+		{Title: "Ok", Get: func(r *gridRow) any { return &r.Ok }, Kind: wid.CellCheckbox, Width: 30, NoSort: true},
+	}
+	state := &wid.GridState{}
+	style := wid.DefaultDataGrid
+	width := float32(0)
+	frame := func(f func()) {
+		w.StartFrame()
+		f()
+		ctx := wid.NewCtx(w)
+		if width > 0 {
+			ctx.Rect.W = width
+		}
+		wid.Grid(state, &data, columns, &style)(ctx)
+		w.EndFrame()
+	}
+	click := func(p f32.Pos) {
+		frame(func() { w.SimLeftClick(p.X, p.Y) })
+	}
+	frame(func() {})
+
+	// Only the visible rows are drawn
+	edits := 0
+	for _, n := range w.AccessTree().Root.Children {
+		if n.Role == sys.RoleEdit {
+			edits++
+		}
+	}
+	if edits == 0 || edits > 30 {
+		t.Errorf("Expected only the visible rows, got %d edits", edits)
+	}
+
+	// Select the second row, then sort by age
+	row := accessNode(w, sys.RoleLabel, "199")
+	if row == nil {
+		t.Fatalf("Age of second row not found")
+	}
+	frame(func() { w.SimLeftBtnPress(row.Bounds.X+5, row.Bounds.Y+5) })
+	frame(func() { w.SimLeftBtnRelease(row.Bounds.X+5, row.Bounds.Y+5) })
+	if state.Selected() != 1 {
+		t.Errorf("Expected row 1 selected, got %d", state.Selected())
+	}
+	age := accessNode(w, sys.RoleButton, "Age")
+	click(f32.Pos{X: age.Bounds.X + 5, Y: age.Bounds.Y + 5})
+	if col, desc := state.SortColumn(); col != 1 || desc || data[0].Age != 1 || data[199].Age != 200 {
+		t.Errorf("Expected ascending sort by age, got column %d, first age %d", col, data[0].Age)
+	}
+	if state.Selected() != 198 || data[198].Name != "Person 1" {
+		t.Errorf("Selection should follow the sorted row, got %d", state.Selected())
+	}
+	frame(func() {})
+	if accessNode(w, sys.RoleEdit, "").Value != "Person 199" {
+		t.Errorf("The edits should show the sorted data")
+	}
+	click(f32.Pos{X: age.Bounds.X + 5, Y: age.Bounds.Y + 5})
+	if _, desc := state.SortColumn(); !desc || data[0].Age != 200 {
+		t.Errorf("Second click should sort descending")
+	}
+	ok := accessNode(w, sys.RoleButton, "Ok")
+	click(f32.Pos{X: ok.Bounds.X + 5, Y: ok.Bounds.Y + 5})
+	if col, _ := state.SortColumn(); col != 1 {
+		t.Errorf("Column with NoSort should not sort")
+	}
+
+	// When the grid gets narrower, the columns are fitted again
+	name := accessNode(w, sys.RoleButton, "Name").Bounds
+	width = 300
+	frame(func() {})
+	if n := accessNode(w, sys.RoleButton, "Name").Bounds; n.W != name.W-100 {
+		t.Errorf("Expected the name column to be 100dp narrower, got %0.1f and %0.1f", name.W, n.W)
+	}
+	width = 0
+	frame(func() {})
+
+	// A click on the border between Name and Age does not sort
+	x := age.Bounds.X - style.HandleWidth/2
+	click(f32.Pos{X: x, Y: age.Bounds.Y + 5})
+	if col, desc := state.SortColumn(); col != 1 || !desc {
+		t.Errorf("Clicking a column border should not sort")
+	}
+
+	// Drag the border 30dp to the right
+	frame(func() { w.SimLeftBtnPress(x, age.Bounds.Y+5) })
+	frame(func() { w.SimPos(x+30, age.Bounds.Y+5) })
+	frame(func() { w.SimLeftBtnRelease(x+30, age.Bounds.Y+5) })
+	frame(func() {})
+	if moved := accessNode(w, sys.RoleButton, "Age").Bounds.X; moved != age.Bounds.X+30 {
+		t.Errorf("Expected Age column at %0.1f, got %0.1f", age.Bounds.X+30, moved)
+	}
+	if col, desc := state.SortColumn(); col != 1 || !desc {
+		t.Errorf("Dragging a column border should not sort")
+	}
+
+	// The dragged column keeps its width
+	width = 300
+	frame(func() {})
+	if n := accessNode(w, sys.RoleButton, "Name").Bounds; n.W != name.W+30 {
+		t.Errorf("Expected the dragged column to keep its width %0.1f, got %0.1f", name.W+30, n.W)
+	}
+}
+
+func TestGridScrollX(t *testing.T) {
+	slog.Info("TestGridScrollX")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 400, 300, "Test", 1, 1.0)
+	w.Focused = true
+	data := []gridRow{{Name: "Ann", Age: 1}, {Name: "Bob", Age: 0}}
+	other := 1
+	columns := []wid.GridColumn[gridRow]{
+		{Title: "Name", Get: func(r *gridRow) any { return &r.Name }, Kind: wid.CellEdit},
+		{Title: "Age", Get: func(r *gridRow) any { return &r.Age }, Kind: wid.CellCombo, Width: 80, Items: []string{"a", "b"}},
+	}
+	state := &wid.GridState{}
+	style := wid.DefaultDataGrid
+	style.Scroll.ContentWidth = 1000
+	frame := func(f func()) {
+		w.StartFrame()
+		f()
+		wid.Show(wid.Col(nil,
+			wid.Grid(state, &data, columns, &style),
+			wid.Combo(&other, []string{"x", "y"}, "", nil),
+		))
+		w.EndFrame()
+	}
+	frame(func() {})
+	hdr := accessNode(w, sys.RoleButton, "Age").Bounds
+	cell := accessNode(w, sys.RoleEdit, "").Bounds
+	if hdr.X+hdr.W < 400 {
+		t.Fatalf("Expected the columns to be fitted to 1000dp, Age is at %0.1f", hdr.X)
+	}
+	frame(func() {
+		w.SimPos(100, cell.Y+5)
+		w.SimScroll(-1, 0, 0)
+	})
+	frame(func() {})
+	if state.Xpos <= 0 {
+		t.Fatalf("Expected the grid to scroll horizontally")
+	}
+	h, c := accessNode(w, sys.RoleButton, "Age").Bounds, accessNode(w, sys.RoleEdit, "").Bounds
+	if h.X != hdr.X-state.Xpos || c.X != cell.X-state.Xpos {
+		t.Errorf("Expected the header and the rows to scroll together, moved %0.1f and %0.1f", hdr.X-h.X, cell.X-c.X)
+	}
+
+	// Sorting only drops the states of the grid's own cells
+	other = 0
+	ages := wid.ComboStateMap[&data[0].Age]
+	if wid.ComboStateMap[&other] == nil || ages == nil {
+		t.Fatalf("Expected combo states")
+	}
+	state.Xpos = 0
+	frame(func() {})
+	name := accessNode(w, sys.RoleButton, "Name").Bounds
+	click := func() {
+		w.LeftBtnUpTime = time.Time{}
+		frame(func() { w.SimLeftBtnPress(name.X+5, name.Y+5) })
+		frame(func() { w.SimLeftBtnRelease(name.X+5, name.Y+5) })
+	}
+	click()
+	click()
+	if col, desc := state.SortColumn(); col != 0 || !desc || data[0].Name != "Bob" {
+		t.Fatalf("Expected the rows to be sorted by name, descending")
+	}
+	if wid.ComboStateMap[&other] == nil || wid.ComboStateMap[&data[0].Age] == ages {
+		t.Errorf("Expected only the grid's combo states to be cleared")
+	}
+}
@@ -0,0 +1,37 @@
+package test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/jkvatne/jkvgui/f32"
+	"github.com/jkvatne/jkvgui/sys"
+)
+
+func TestCapture(t *testing.T) {
+	slog.Info("TestCapture")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	w := sys.CreateWindow(0, 0, 200, 100, "Test", 1, 1.0)
+	if sys.Headless && (w.WidthPx != 200 || w.HeightPx != 100 || w.Window != nil) {
+		t.Errorf("Headless window should have no glfw window, and size 200x100, got %dx%d", w.WidthPx, w.HeightPx)
+	}
+	for frame := 0; frame < 2; frame++ {
+		w.StartFrame()
+		w.Gd.SolidRect(f32.Rect{X: 0, Y: 0, W: 200, H: 100}, f32.White)
+		w.Gd.SolidRect(f32.Rect{X: 10, Y: 20, W: 30, H: 40}, f32.Red)
+		img := sys.Capture(w, 0, 0, 200, 100)
+		if r, g, b, _ := img.At(25, 40).RGBA(); r>>8 != 255 || g>>8 != 0 || b>>8 != 0 {
+			t.Errorf("Expected red at 25,40 in frame %d, got %d,%d,%d", frame, r>>8, g>>8, b>>8)
+		}
+		if r, g, b, _ := img.At(100, 80).RGBA(); r>>8 != 255 || g>>8 != 255 || b>>8 != 255 {
+			t.Errorf("Expected white at 100,80 in frame %d, got %d,%d,%d", frame, r>>8, g>>8, b>>8)
+		}
+		w.EndFrame()
+	}
+	w.SetShouldClose(true)
+	if sys.Running() {
+		t.Errorf("Running() should be false when the only window is closed")
+	}
+}
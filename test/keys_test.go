@@ -0,0 +1,121 @@
+package test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/wid"
+)
+
+func TestKeyState(t *testing.T) {
+	slog.Info("TestKeyState")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w1 := sys.CreateWindow(0, 0, 200, 100, "Test1", 1, 1.0)
+	w2 := sys.CreateWindow(0, 0, 200, 100, "Test2", 1, 1.0)
+	w1.HandleKey(sys.KeyLeftControl, 0, sys.Press, 0)
+	w1.HandleKey(sys.KeySpace, 0, sys.Press, sys.ModControl)
+	if !w1.KeyDown(sys.KeySpace) || w2.KeyDown(sys.KeySpace) {
+		t.Errorf("Key state should be kept for each window")
+	}
+	if !w1.ModDown(sys.ModControl) || w1.ModDown(sys.ModControl|sys.ModShift) || w2.ModDown(sys.ModControl) {
+		t.Errorf("Expected only Ctrl down in the first window, got %v", w1.ModsDown())
+	}
+	w1.HandleKey(sys.KeySpace, 0, sys.Release, sys.ModControl)
+	if w1.KeyDown(sys.KeySpace) || !w1.ModDown(sys.ModControl) {
+		t.Errorf("Space should be released")
+	}
+	// The deprecated KeyIsDown follows the focused window
+	w1.HandleFocus(true)
+	w2.HandleKey(sys.KeySpace, 0, sys.Press, 0)
+	if !sys.KeyIsDown[sys.KeyLeftControl] || sys.KeyIsDown[sys.KeySpace] {
+		t.Errorf("KeyIsDown should give the keys of the focused window")
+	}
+	w2.HandleFocus(true)
+	if !sys.KeyIsDown[sys.KeySpace] {
+		t.Errorf("KeyIsDown should follow the focus")
+	}
+	w2.HandleKey(sys.KeySpace, 0, sys.Release, 0)
+	// Keys are released when the window loses focus
+	w1.HandleFocus(false)
+	if w1.ModsDown() != 0 {
+		t.Errorf("Key state should be cleared when the focus is lost")
+	}
+	if w1.KeyDown(-1) || w1.KeyDown(10000) {
+		t.Errorf("Unknown keys are never down")
+	}
+}
+
+func TestLayoutShortcuts(t *testing.T) {
+	if !sys.Headless {
+		t.Skip("The keyboard layout can only be simulated in headless mode")
+	}
+	slog.Info("TestLayoutShortcuts")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 200, 100, "Test", 1, 1.0)
+	w.Focused = true
+	// On AZERTY keyboards, the key labelled Z is where W is on US keyboards
+	sys.HeadlessKeyNames[sys.KeyA+'W'-'A'] = "z"
+	sys.HeadlessKeyNames[sys.KeyA+'Z'-'A'] = "w"
+	defer clear(sys.HeadlessKeyNames)
+	if sys.KeyName(sys.KeyA+'W'-'A', 0) != "z" || sys.KeyName(sys.KeyC, 0) != "c" || sys.KeyName(sys.KeyEnter, 0) != "" {
+		t.Errorf("Unexpected key names")
+	}
+	undo, byPos := 0, 0
+	_, _ = w.Shortcuts.Bind("Ctrl+Z", func() { undo++ }, nil)
+	// Bound to the key position
+	_, _ = w.Shortcuts.BindShortcut(sys.Shortcut{Key: sys.KeyA + 'Q' - 'A', Mods: sys.ModControl}, func() { byPos++ }, nil)
+	w.StartFrame()
+	w.EndFrame()
+	w.HandleKey(sys.KeyA+'Z'-'A', 0, sys.Release, sys.ModControl)
+	w.HandleKey(sys.KeyA+'W'-'A', 0, sys.Release, sys.ModControl)
+	w.HandleKey(sys.KeyA+'Q'-'A', 0, sys.Release, sys.ModControl)
+	w.StartFrame()
+	w.EndFrame()
+	if undo != 1 || byPos != 1 {
+		t.Errorf("Expected Ctrl+Z to match the key producing z, got undo=%d, byPos=%d", undo, byPos)
+	}
+}
+
+func TestNonLatinShortcuts(t *testing.T) {
+	if !sys.Headless {
+		t.Skip("The keyboard layout can only be simulated in headless mode")
+	}
+	slog.Info("TestNonLatinShortcuts")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 300, 100, "Test", 1, 1.0)
+	w.Focused = true
+	// A Russian layout gives Cyrillic letters, so the shortcuts are matched by key position
+	sys.HeadlessKeyNames[sys.KeyC] = "с"
+	sys.HeadlessKeyNames[sys.KeyV] = "м"
+	sys.HeadlessKeyNames[sys.KeyA+'S'-'A'] = "ы"
+	defer clear(sys.HeadlessKeyNames)
+	saved := 0
+	_, _ = w.Shortcuts.Bind("Ctrl+S", func() { saved++ }, nil)
+	text := ""
+	form := wid.Edit(&text, "", nil, nil)
+	w.SetFocusedTag(&text)
+	frame := func() {
+		w.StartFrame()
+		wid.Show(form)
+		w.EndFrame()
+	}
+	frame()
+	sys.SetClipboardString("Привет")
+	w.HandleKey(sys.KeyA+'S'-'A', 0, sys.Release, sys.ModControl)
+	w.HandleKey(sys.KeyV, 0, sys.Release, sys.ModControl)
+	frame()
+	frame()
+	if n := accessNode(w, sys.RoleEdit, ""); saved != 1 || n.Value != "Привет" {
+		t.Errorf("Expected Ctrl+S and the paste shortcut to match by key position, got saved=%d, text=%q", saved, n.Value)
+	}
+}
@@ -0,0 +1,210 @@
+package test
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/jkvatne/jkvgui/gpu"
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/wid"
+)
+
+// findNode searches the accessibility tree, including the children of the nodes
+func findNode(nodes []*sys.AccessNode, role sys.Role, name string) *sys.AccessNode {
+	for _, n := range nodes {
+		if n.Role == role && n.Name == name {
+			return n
+		}
+		if c := findNode(n.Children, role, name); c != nil {
+			return c
+		}
+	}
+	return nil
+}
+
+func TestMenu(t *testing.T) {
+	slog.Info("TestMenu")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 400, 300, "Test", 1, 1.0)
+	w.Focused = true
+	var chosen []string
+	action := func(s string) func() { return func() { chosen = append(chosen, s) } }
+	saved, clicked, wrap := 0, 0, false
+	b, _ := w.Shortcuts.Bind("Ctrl+S", func() { saved++ }, nil)
+	menus := []wid.MenuItem{
+		{Text: "File", Items: []wid.MenuItem{
+			{Text: "New", Action: action("New")},
+			{Text: "Open", Icon: gpu.ContentOpen, Shortcut: "Ctrl+O", Action: action("Open")},
+			wid.MenuSeparator,
+			{Text: "Recent", Items: []wid.MenuItem{
+				{Text: "a.txt", Action: action("a.txt")},
+				{Text: "b.txt", Action: action("b.txt")},
+			}},
+			{Text: "Print", Disabled: true, Action: action("Print")},
+			{Text: "Save", Binding: b},
+		}},
+		{Text: "Edit", Items: []wid.MenuItem{
+			{Text: "Cut", Icon: gpu.ContentCut, Action: action("Cut")},
+			{Text: "Copy", Icon: gpu.ContentCopy, Action: action("Copy")},
+		}},
+		{Text: "View", Items: []wid.MenuItem{
+			{Text: "Wrap", Checked: &wrap},
+		}},
+	}
+	barState := &wid.MenuState{}
+	popupState := &wid.MenuState{}
+	popupItems := []wid.MenuItem{{Text: "Paste", Icon: gpu.ContentPaste, Action: action("Paste")}}
+	form := wid.Col(nil,
+		wid.MenuBar(barState, menus, nil),
+		wid.ContextMenu(popupState, popupItems, nil, wid.Btn("Button", nil, func() { clicked++ }, nil, "")),
+	)
+	frame := func(input func()) {
+		w.StartFrame()
+		if input != nil {
+			input()
+		}
+		wid.Show(form)
+		w.EndFrame()
+	}
+	click := func(n *sys.AccessNode) {
+		x, y := n.Bounds.X+n.Bounds.W/2, n.Bounds.Y+n.Bounds.H/2
+		w.LeftBtnUpTime = time.Time{}
+		frame(func() { w.SimLeftBtnPress(x, y) })
+		frame(func() { w.SimLeftBtnRelease(x, y) })
+		frame(nil)
+	}
+	key := func(k sys.Key, mods sys.ModifierKey) {
+		frame(func() { w.SimKey(k, mods) })
+		frame(nil)
+	}
+	node := func(role sys.Role, name string) *sys.AccessNode {
+		return findNode(w.AccessTree().Root.Children, role, name)
+	}
+
+	frame(nil)
+	bar := node(sys.RoleMenuBar, "")
+	if bar == nil || len(bar.Children) != 3 || node(sys.RoleMenu, "File").State&sys.StateExpanded != 0 {
+		t.Fatalf("Expected a closed menu bar with 3 menus")
+	}
+
+	// Open the File menu with the mouse, and choose Open with the keyboard
+	click(node(sys.RoleMenu, "File"))
+	file := findNode(w.AccessTree().Root.Children[1:], sys.RoleMenu, "File")
+	if !barState.Open() || file == nil || len(file.Children) != 5 {
+		t.Fatalf("Expected the File menu to open with 5 items")
+	}
+	if save := findNode(file.Children, sys.RoleMenuItem, "Save"); save == nil || save.State&sys.StateDisabled != 0 {
+		t.Errorf("Expected an enabled Save item")
+	}
+	if print := findNode(file.Children, sys.RoleMenuItem, "Print"); print == nil || print.State&sys.StateDisabled == 0 {
+		t.Errorf("Expected a disabled Print item")
+	}
+	key(sys.KeyDown, 0)
+	key(sys.KeyDown, 0)
+	if n := node(sys.RoleMenuItem, "Open"); n == nil || n.State&sys.StateSelected == 0 {
+		t.Errorf("Expected Open to be highlighted")
+	}
+	key(sys.KeyEnter, 0)
+	if barState.Open() || len(chosen) != 1 || chosen[0] != "Open" {
+		t.Errorf("Expected Open to be chosen and the menu closed, got %v", chosen)
+	}
+
+	// F10 opens the first menu, the arrow keys move between the menus, and Escape closes them
+	key(sys.KeyF10, 0)
+	key(sys.KeyRight, 0)
+	if n := node(sys.RoleMenu, "Edit"); n == nil || n.State&sys.StateExpanded == 0 {
+		t.Errorf("Right should open the Edit menu")
+	}
+	key(sys.KeyLeft, 0)
+	key(sys.KeyUp, 0)
+	if n := node(sys.RoleMenuItem, "Save"); n == nil || n.State&sys.StateSelected == 0 {
+		t.Errorf("Up should move from New to Save")
+	}
+	key(sys.KeyUp, 0)
+	if n := node(sys.RoleMenuItem, "Recent"); n == nil || n.State&sys.StateSelected == 0 {
+		t.Errorf("Up should skip the disabled Print item")
+	}
+	key(sys.KeyRight, 0)
+	if n := node(sys.RoleMenuItem, "a.txt"); n == nil || n.State&sys.StateSelected == 0 {
+		t.Errorf("Right should open the Recent submenu")
+	}
+	key(sys.KeyEscape, 0)
+	if !barState.Open() || node(sys.RoleMenuItem, "a.txt") != nil {
+		t.Errorf("Escape should close the submenu only")
+	}
+	key(sys.KeyEscape, 0)
+	if barState.Open() {
+		t.Errorf("Escape should close the menu")
+	}
+
+	// Hovering opens a submenu, and the binding's action is used for Save
+	click(node(sys.RoleMenu, "File"))
+	recent := node(sys.RoleMenuItem, "Recent")
+	frame(func() { w.SimPos(recent.Bounds.X+5, recent.Bounds.Y+5) })
+	frame(nil)
+	click(node(sys.RoleMenuItem, "b.txt"))
+	if barState.Open() || chosen[len(chosen)-1] != "b.txt" {
+		t.Errorf("Expected b.txt to be chosen, got %v", chosen)
+	}
+	click(node(sys.RoleMenu, "File"))
+	click(node(sys.RoleMenuItem, "Print"))
+	if !barState.Open() || chosen[len(chosen)-1] == "Print" {
+		t.Errorf("Disabled items should not be chosen")
+	}
+	click(node(sys.RoleMenuItem, "Save"))
+	if saved != 1 || node(sys.RoleMenuItem, "Save") != nil {
+		t.Errorf("Expected Save to run the binding's action")
+	}
+
+	// Checkable items
+	click(node(sys.RoleMenu, "View"))
+	if n := node(sys.RoleCheckMenuItem, "Wrap"); n == nil || n.State&sys.StateChecked != 0 {
+		t.Errorf("Expected an unchecked Wrap item")
+	}
+	click(node(sys.RoleCheckMenuItem, "Wrap"))
+	if !wrap {
+		t.Errorf("Wrap should be checked")
+	}
+
+	// A click outside closes the menu, and is not seen by the widgets below
+	btn := node(sys.RoleButton, "Button")
+	click(node(sys.RoleMenu, "Edit"))
+	click(btn)
+	if barState.Open() || clicked != 0 {
+		t.Errorf("A click outside should only close the menu, clicked=%d", clicked)
+	}
+	click(btn)
+	if clicked != 1 {
+		t.Errorf("The button should work when the menu is closed")
+	}
+
+	// Context menu
+	x, y := btn.Bounds.X+10, btn.Bounds.Y+5
+	w.RightBtnUpTime = time.Time{}
+	frame(func() { w.SimRightBtnPress(x, y) })
+	frame(func() { w.SimRightBtnRelease(x, y) })
+	frame(nil)
+	paste := node(sys.RoleMenuItem, "Paste")
+	if !popupState.Open() || paste == nil || paste.Bounds.X < x || paste.Bounds.Y < y {
+		t.Fatalf("Expected the context menu at the mouse position")
+	}
+	click(paste)
+	if popupState.Open() || chosen[len(chosen)-1] != "Paste" {
+		t.Errorf("Expected Paste to be chosen, got %v", chosen)
+	}
+
+	// Shift+F10 opens the context menu of the focused button, and F10 the menu bar
+	key(sys.KeyF10, sys.ModShift)
+	if !popupState.Open() || barState.Open() {
+		t.Errorf("Shift+F10 should open the context menu, not the menu bar")
+	}
+	key(sys.KeyEscape, 0)
+	key(sys.KeyF10, 0)
+	if popupState.Open() || !barState.Open() {
+		t.Errorf("F10 should open the menu bar, not the context menu")
+	}
+}
@@ -0,0 +1,92 @@
+package test
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/jkvatne/jkvgui/f32"
+	"github.com/jkvatne/jkvgui/sys"
+)
+
+func TestRightAndMiddleButtons(t *testing.T) {
+	slog.Info("TestRightAndMiddleButtons")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 200, 200, "Test", 1, 1.0)
+	w.Focused = true
+	r := f32.Rect{X: 10, Y: 10, W: 50, H: 50}
+	w.StartFrame()
+
+	w.SimRightBtnPress(20, 20)
+	if !w.RightBtnPressed(r) || w.LeftBtnDown() {
+		t.Errorf("Right button press not detected")
+	}
+	w.SimRightBtnRelease(20, 20)
+	if !w.RightBtnClick(r) || w.RightBtnClick(r) {
+		t.Errorf("Right button click should be reported once")
+	}
+	w.SimRightDoubleClick(20, 20)
+	if w.RightBtnDoubleClick(f32.Rect{X: 100, Y: 100, W: 10, H: 10}) || !w.RightBtnDoubleClick(r) {
+		t.Errorf("Right button double click not detected inside rectangle")
+	}
+
+	w.SimMiddleBtnPress(20, 20)
+	if !w.MiddleBtnPressed(r) || !w.MiddleBtnDown() {
+		t.Errorf("Middle button press not detected")
+	}
+	w.SimMiddleBtnRelease(20, 20)
+	w.SimMiddleBtnPress(20, 20)
+	w.SimMiddleBtnRelease(20, 20)
+	if !w.MiddleBtnDoubleClick(r) {
+		t.Errorf("Middle button double click not detected")
+	}
+	w.EndFrame()
+}
+
+func TestLongPressAndDrag(t *testing.T) {
+	slog.Info("TestLongPressAndDrag")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 200, 200, "Test", 1, 1.0)
+	w.Focused = true
+	r := f32.Rect{X: 10, Y: 10, W: 50, H: 50}
+	oldTime := sys.LongPressTime
+	sys.LongPressTime = 10 * time.Millisecond
+	defer func() { sys.LongPressTime = oldTime }()
+	w.StartFrame()
+
+	w.SimRightBtnPress(20, 20)
+	if w.RightBtnLongPress(r) {
+		t.Errorf("Long press reported too early")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if !w.RightBtnLongPress(r) || w.RightBtnLongPress(r) {
+		t.Errorf("Long press should be reported once")
+	}
+	w.SimRightBtnRelease(20, 20)
+	if w.RightBtnClick(r) {
+		t.Errorf("Long press should not give a click")
+	}
+
+	// Middle button drag, used for panning
+	w.SimMiddleBtnPress(20, 20)
+	start := w.StartDrag()
+	w.SimPos(80, 90)
+	if !w.Dragging || w.DragButton != sys.MouseButtonMiddle || w.MousePos().X-start.X != 60 || w.MousePos().Y-start.Y != 70 {
+		t.Errorf("Middle button drag not detected")
+	}
+	w.SimLeftBtnRelease(80, 90)
+	if !w.Dragging {
+		t.Errorf("Releasing the left button should not stop a middle button drag")
+	}
+	w.SimMiddleBtnRelease(80, 90)
+	if w.Dragging {
+		t.Errorf("Middle button drag did not end on release")
+	}
+	w.EndFrame()
+}
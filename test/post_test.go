@@ -0,0 +1,45 @@
+package test
+
+import (
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/wid"
+)
+
+func TestPost(t *testing.T) {
+	slog.Info("TestPost")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 400, 200, "Test", 1, 1.0)
+	var lines []string
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sys.Post(w, func() { lines = append(lines, "A line") })
+		}()
+	}
+	wg.Wait()
+	if len(lines) != 0 {
+		t.Errorf("Posted functions should not run before the next frame")
+	}
+	// Posting wakes the event loop
+	start := time.Now()
+	sys.PollEvents()
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("PollEvents was not woken by Post")
+	}
+	w.StartFrame()
+	if len(lines) != 10 {
+		t.Errorf("Expected 10 lines at the start of the frame, got %d", len(lines))
+	}
+	wid.Show(wid.Memo(&lines, nil))
+	w.EndFrame()
+}
@@ -0,0 +1,78 @@
+package test
+
+import (
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/wid"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	slog.Info("TestRecordAndReplay")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 600, 70, "Test", 1, 1.0)
+	w.Focused = true
+	value := ""
+	entered := 0
+	edit := wid.Edit(&value, "Test", func() { entered++ }, nil)
+	frame := func() {
+		w.StartFrame()
+		wid.Display(w, 10, 10, 570, edit)
+		w.EndFrame()
+	}
+	filename := filepath.Join(t.TempDir(), "events.json")
+	if err := w.StartRecording(filename); err != nil {
+		t.Fatalf("StartRecording failed: %v", err)
+	}
+	w.SetFocusedTag(&value)
+	frame()
+	w.HandleChar('a')
+	w.HandleChar('b')
+	frame()
+	w.HandleKey(sys.KeyBackspace, 0, sys.Press, 0)
+	w.HandleKey(sys.KeyBackspace, 0, sys.Release, 0)
+	w.HandleMouseScroll(0, 1)
+	frame()
+	w.HandleChar('c')
+	w.HandleKey(sys.KeyEnter, 0, sys.Release, 0)
+	frame()
+	if err := w.StopRecording(); err != nil || w.Recording() {
+		t.Errorf("StopRecording failed: %v", err)
+	}
+	if value != "ac" || entered != 1 {
+		t.Errorf("Expected ac to be entered, got %q", value)
+	}
+
+	events, err := sys.LoadRecording(filename)
+	if err != nil || len(events) != 7 {
+		t.Fatalf("Expected 7 events, got %d, error %v", len(events), err)
+	}
+	frames := []int{2, 2, 3, 3, 3, 4, 4}
+	for i, e := range events {
+		if e.Frame != frames[i] {
+			t.Errorf("Event %d (%s) should be in frame %d, was %d", i, e.Type, frames[i], e.Frame)
+		}
+	}
+	if events[0].Type != "char" || events[0].Rune != 'a' || events[2].Type != "key" || events[4].Type != "scroll" {
+		t.Errorf("Wrong events recorded: %+v", events)
+	}
+
+	// Replay to a new, empty edit
+	replayed := ""
+	edit = wid.Edit(&replayed, "Test", func() { entered++ }, nil)
+	w.SetFocusedTag(&replayed)
+	if err = w.Play(filename); err != nil {
+		t.Fatalf("Play failed: %v", err)
+	}
+	for i := 0; i < 10 && w.Playing(); i++ {
+		frame()
+	}
+	if w.Playing() || replayed != "ac" || entered != 2 {
+		t.Errorf("Replay should give ac, got %q", replayed)
+	}
+}
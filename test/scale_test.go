@@ -0,0 +1,66 @@
+package test
+
+import (
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/jkvatne/jkvgui/gpu/font"
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/wid"
+)
+
+func TestScaleChange(t *testing.T) {
+	if !sys.Headless {
+		t.Skip("The content scale can only be changed in headless mode")
+	}
+	slog.Info("TestScaleChange")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = false
+	slog.SetLogLoggerLevel(slog.LevelError)
+	oldScale := sys.HeadlessScale
+	defer func() { sys.HeadlessScale = oldScale }()
+	sys.HeadlessScale = 1.0
+	w := sys.CreateWindow(0, 0, 300, 100, "Test", 1, 1.0)
+	changed := 0
+	w.OnScaleChange = func() { changed++ }
+	frame := func() {
+		w.StartFrame()
+		wid.Show(wid.Label("Some text", nil))
+		w.EndFrame()
+	}
+	frame()
+	f := font.Get(wid.DefaultLabel.FontNo)
+	if f.Dpi() != font.DefaultDpi || !slices.Equal(f.Dpis(), []float32{font.DefaultDpi}) {
+		t.Errorf("Expected fonts at %v dpi, got %v", font.DefaultDpi, f.Dpis())
+	}
+	w1 := f.Width("Some text")
+	// Moving the window to a monitor with scale 2.0
+	sys.HeadlessScale = 2.0
+	w.HandleScale(2.0, 2.0)
+	if changed != 1 || w.Gd.ScaleX != 2.0 || w.WidthDp != 150 {
+		t.Errorf("Expected scale 2.0 and width 150dp, got %v and %v", w.Gd.ScaleX, w.WidthDp)
+	}
+	w.HandleScale(2.0, 2.0)
+	if changed != 1 {
+		t.Errorf("OnScaleChange should only be called when the scale changes")
+	}
+	frame()
+	// The font is the same, and has glyphs for both dpi values
+	if font.Get(wid.DefaultLabel.FontNo) != f || !slices.Equal(f.Dpis(), []float32{font.DefaultDpi, 2 * font.DefaultDpi}) {
+		t.Errorf("Expected glyphs generated at %v dpi, got %v", 2*font.DefaultDpi, f.Dpis())
+	}
+	if d := f.Width("Some text") - w1; d < -2 || d > 2 {
+		t.Errorf("Text width in dp should not change with the dpi, difference was %v", d)
+	}
+	// The least recently used glyphs are freed, but never the ones the font was loaded with
+	oldMax := font.MaxFontSets
+	defer func() { font.MaxFontSets = oldMax }()
+	font.MaxFontSets = 2
+	sys.HeadlessScale = 3.0
+	frame()
+	if !slices.Equal(f.Dpis(), []float32{font.DefaultDpi, 3 * font.DefaultDpi}) {
+		t.Errorf("Expected the glyphs for %v dpi to be freed, got %v", 2*font.DefaultDpi, f.Dpis())
+	}
+}
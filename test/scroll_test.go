@@ -0,0 +1,141 @@
+package test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/wid"
+)
+
+func TestHorizontalScroll(t *testing.T) {
+	slog.Info("TestHorizontalScroll")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 200, 200, "Test", 1, 1.0)
+	w.Focused = true
+	// Shift + scroll-wheel is mapped to horizontal scrolling
+	w.LastMods = sys.ModShift
+	w.HandleMouseScroll(0, -1)
+	w.LastMods = 0
+	if w.ScrolledY() != 0 || w.ScrolledX() != -1 {
+		t.Errorf("Shift+wheel should scroll horizontally")
+	}
+
+	state := &wid.ScrollState{}
+	style := wid.DefaultScrollStyle
+	style.ContentWidth = 1000
+	var lines []wid.Wid
+	for i := 0; i < 20; i++ {
+		lines = append(lines, wid.Label("A line that is very much wider than the window", nil))
+	}
+	scroller := wid.ScrollerXY(state, &style, lines...)
+	ctx := wid.NewCtx(w)
+	ctx.Mode = wid.RenderChildren
+	w.StartFrame()
+	w.SimPos(100, 100)
+	scroller(ctx)
+	if state.Xmax != 1000 || state.Xpos != 0 {
+		t.Errorf("Expected Xmax=1000 and Xpos=0, got %v, %v", state.Xmax, state.Xpos)
+	}
+	w.ScrolledDistX = -1
+	scroller(ctx)
+	if state.Xpos <= 0 {
+		t.Errorf("Expected scroller to move right, Xpos=%v", state.Xpos)
+	}
+	w.ScrolledDistX = 1000
+	scroller(ctx)
+	if state.Xpos != 0 {
+		t.Errorf("Expected scroller to stop at the left edge, Xpos=%v", state.Xpos)
+	}
+	w.EndFrame()
+}
+
+func TestScrollKeys(t *testing.T) {
+	slog.Info("TestScrollKeys")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 200, 200, "Test", 1, 1.0)
+	w.Focused = true
+	var cb [30]bool
+	var lines []wid.Wid
+	for i := range cb {
+		lines = append(lines, wid.Row(nil, wid.Checkbox("Check", &cb[i], nil, nil, "")))
+	}
+	state := &wid.ScrollState{}
+	form := wid.Scroller(state, nil, lines...)
+	frame := func(key sys.Key) {
+		w.StartFrame()
+		w.SimPos(100, 100)
+		if key != 0 {
+			w.SimKey(key, 0)
+		}
+		wid.Show(form)
+		w.EndFrame()
+	}
+	w.RequestFocus(&cb[0])
+	frame(0)
+	// An arrow key that moves the focus should not scroll as well
+	frame(sys.KeyDown)
+	if w.CurrentTag != &cb[1] {
+		t.Errorf("Expected KeyDown to move the focus to checkbox 1")
+	}
+	if state.PendingScroll != 0 || state.Ypos != 0 {
+		t.Errorf("Expected no scrolling when the focus moved, got PendingScroll=%v, Ypos=%v", state.PendingScroll, state.Ypos)
+	}
+	// Keys not used by the focus navigation scroll
+	frame(sys.KeyEnd)
+	for i := 0; i < 50 && state.PendingScroll != 0; i++ {
+		frame(0)
+	}
+	if state.Ypos <= 0 {
+		t.Errorf("Expected KeyEnd to scroll down, Ypos=%v", state.Ypos)
+	}
+}
+
+func TestScrollThumbDrag(t *testing.T) {
+	slog.Info("TestScrollThumbDrag")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 200, 200, "Test", 1, 1.0)
+	w.Focused = true
+	var lines []wid.Wid
+	for i := 0; i < 50; i++ {
+		lines = append(lines, wid.Label("Line", nil))
+	}
+	state := &wid.ScrollState{}
+	form := wid.Scroller(state, nil, lines...)
+	frame := func(input func()) {
+		w.StartFrame()
+		if input != nil {
+			input()
+		}
+		wid.Show(form)
+		w.EndFrame()
+	}
+	settle := func() {
+		for i := 0; i < 50 && state.PendingScroll != 0; i++ {
+			frame(nil)
+		}
+	}
+	frame(nil)
+	frame(func() { w.SimLeftBtnPress(195, 5) })
+	frame(func() { w.SimPos(195, 25) })
+	settle()
+	if state.Ypos <= 0 || state.Ypos >= state.Ymax-200 {
+		t.Errorf("Expected the thumb drag to scroll part of the way, Ypos=%v", state.Ypos)
+	}
+	// Moving the mouse below the scroller drags the thumb to the end
+	frame(func() { w.SimPos(195, 1000) })
+	settle()
+	if state.Ypos != state.Ymax-200 {
+		t.Errorf("Expected the drag outside the scroller to reach the end, Ypos=%v, Ymax=%v", state.Ypos, state.Ymax)
+	}
+	frame(func() { w.SimLeftBtnRelease(195, 1000) })
+}
@@ -0,0 +1,102 @@
+package test
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/wid"
+)
+
+func TestParseShortcut(t *testing.T) {
+	for _, s := range []string{"Ctrl+S", "Ctrl+Shift+F", "F5", "Alt+Enter", "Ctrl+PgDn"} {
+		sc, err := sys.ParseShortcut(s)
+		if err != nil {
+			t.Errorf("Could not parse %s: %v", s, err)
+		} else if sc.String() != s {
+			t.Errorf("Expected %s, got %s", s, sc.String())
+		}
+	}
+	sc, _ := sys.ParseShortcut("control + shift + f")
+	if sc.Key != sys.KeyA+5 || sc.Mods != sys.ModControl|sys.ModShift {
+		t.Errorf("Shortcut parsing should ignore case and spaces, got %s", sc.String())
+	}
+	if _, err := sys.ParseShortcut("Ctrl+Hyper+S"); err == nil {
+		t.Errorf("Expected error for unknown modifier")
+	}
+}
+
+func TestShortcuts(t *testing.T) {
+	slog.Info("TestShortcuts")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 600, 70, "Test", 1, 1.0)
+	w.Focused = true
+	saved, searched, refreshed := 0, 0, 0
+	value := ""
+	b, err := w.Shortcuts.Bind("Ctrl+S", func() { saved++ }, nil)
+	if err != nil || b.Hint("Save") != "Save (Ctrl+S)" {
+		t.Errorf("Bind failed")
+	}
+	if _, err := w.Shortcuts.Bind("ctrl+s", func() {}, nil); !errors.Is(err, sys.ErrShortcutConflict) {
+		t.Errorf("Expected conflict, got %v", err)
+	}
+	// Only active when the edit is focused
+	_, _ = w.Shortcuts.Bind("Ctrl+Shift+F", func() { searched++ }, &value)
+	g, _ := sys.GlobalShortcuts.Bind("F5", func() { refreshed++ }, nil)
+	defer sys.GlobalShortcuts.Unbind(g)
+	form := wid.Scope(&value, wid.Edit(&value, "Test", nil, nil))
+
+	other := 0
+	w.SetFocusedTag(&other)
+	w.StartFrame()
+	wid.Display(w, 10, 10, 570, form)
+	w.EndFrame()
+	w.HandleKey(sys.KeyA+5, 0, sys.Release, sys.ModControl|sys.ModShift)
+	w.HandleKey(sys.KeyF1+4, 0, sys.Release, 0)
+	w.HandleKey(sys.KeyA+18, 0, sys.Release, sys.ModControl)
+	w.StartFrame()
+	w.EndFrame()
+	if saved != 1 || refreshed != 1 || searched != 0 {
+		t.Errorf("Expected one save and one refresh, got saved=%d, refreshed=%d, searched=%d", saved, refreshed, searched)
+	}
+
+	// Focus the edit, and the scoped binding becomes active
+	w.SetFocusedTag(&value)
+	w.StartFrame()
+	wid.Display(w, 10, 10, 570, form)
+	w.EndFrame()
+	w.HandleKey(sys.KeyA+5, 0, sys.Release, sys.ModControl|sys.ModShift)
+	w.StartFrame()
+	w.EndFrame()
+	if searched != 1 {
+		t.Errorf("Scoped shortcut not run")
+	}
+
+	// The focused edit gets Ctrl+V before the global binding
+	pasted := 0
+	p, _ := sys.GlobalShortcuts.Bind("Ctrl+V", func() { pasted++ }, nil)
+	defer sys.GlobalShortcuts.Unbind(p)
+	sys.SetClipboardString("abc")
+	w.HandleKey(sys.KeyV, 0, sys.Release, sys.ModControl)
+	for range 2 {
+		w.StartFrame()
+		wid.Display(w, 10, 10, 570, form)
+		w.EndFrame()
+	}
+	if n := accessNode(w, sys.RoleEdit, "Test"); pasted != 0 || n == nil || n.Value != "abc" {
+		t.Errorf("Expected the edit to paste, and the binding not to run, got pasted=%d", pasted)
+	}
+	// The binding is used when the key is not taken by a widget
+	w.SetFocusedTag(&other)
+	w.HandleKey(sys.KeyV, 0, sys.Release, sys.ModControl)
+	w.StartFrame()
+	wid.Display(w, 10, 10, 570, form)
+	w.EndFrame()
+	if pasted != 1 {
+		t.Errorf("Expected the binding to run when the edit is not focused")
+	}
+}
@@ -0,0 +1,131 @@
+package test
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/jkvatne/jkvgui/gpu/font"
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/wid"
+)
+
+func TestSlider(t *testing.T) {
+	slog.Info("TestSlider")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 400, 300, "Test", 1, 1.0)
+	w.Focused = true
+	value := float32(20)
+	low, high := 20, 80
+	level := 0.0
+	style := *wid.DefaultSlider
+	style.Ticks = 4
+	style.Labels = true
+	style.ShowValue = true
+	style.Format = "%.0f°C"
+	form := wid.Col(nil,
+		wid.Slider(&value, 0, 100, 5, &style, "Setpoint"),
+		wid.RangeSlider(&low, &high, 0, 100, 1, nil, "Limits"),
+		wid.Slider(&level, 0, 1, 0.1, wid.DefaultSlider.Vert(150), "Level"),
+	)
+	frame := func(input func()) {
+		w.StartFrame()
+		if input != nil {
+			input()
+		}
+		wid.Show(form)
+		w.EndFrame()
+	}
+	key := func(k sys.Key) {
+		frame(func() { w.SimKey(k, 0) })
+		frame(nil)
+	}
+	drag := func(x1, y1, x2, y2 float32) {
+		w.LeftBtnUpTime = time.Time{}
+		frame(func() { w.SimLeftBtnPress(x1, y1) })
+		frame(func() { w.SimPos(x2, y2) })
+		frame(func() { w.SimLeftBtnRelease(x2, y2) })
+		frame(nil)
+	}
+	frame(nil)
+	setpoint := accessNode(w, sys.RoleSlider, "Setpoint")
+	if setpoint == nil || setpoint.Value != "20°C" {
+		t.Fatalf("Expected a slider with the value 20°C, got %v", setpoint)
+	}
+
+	// Pressing the track moves the thumb to the mouse, and it follows the mouse while dragging.
+	// The track is inset by the padding and half the width of the widest label.
+	r := setpoint.Bounds
+	y := r.Y + r.H/2
+	margin := style.Padding.L + font.Get(style.FontNo).Width("100°C")/2
+	x0, x1 := r.X+margin, r.X+r.W-margin
+	pos := func(f float32) float32 { return x0 + (x1-x0)*f }
+	drag(pos(0.5), y, pos(0.74), y)
+	if value != 75 || w.CurrentTag != &value {
+		t.Errorf("Expected the value 75 after dragging, got %v", value)
+	}
+	drag(pos(0.5), y, r.X+r.W+100, y)
+	if value != 100 {
+		t.Errorf("Dragging outside should give the maximum, got %v", value)
+	}
+
+	// Keys
+	key(sys.KeyLeft)
+	key(sys.KeyPageDown)
+	if value != 45 {
+		t.Errorf("Expected 45 after Left and PageDown, got %v", value)
+	}
+	key(sys.KeyHome)
+	if value != 0 || accessNode(w, sys.RoleSlider, "Setpoint").Value != "0°C" {
+		t.Errorf("Home should give the minimum, got %v", value)
+	}
+
+	// The range slider moves the nearest thumb, and the low value can not pass the high value
+	var thumbs []*sys.AccessNode
+	for _, n := range w.AccessTree().Root.Children {
+		if n.Name == "Limits" {
+			thumbs = append(thumbs, n)
+		}
+	}
+	if len(thumbs) != 2 || thumbs[0].Value != "20" || thumbs[1].Value != "80" {
+		t.Fatalf("Expected two thumbs with the values 20 and 80")
+	}
+	hx, hy := thumbs[1].Bounds.X+thumbs[1].Bounds.W/2, thumbs[1].Bounds.Y+thumbs[1].Bounds.H/2
+	drag(hx+2, hy, thumbs[0].Bounds.X-50, hy)
+	if low != 20 || high != 20 {
+		t.Errorf("Expected high to stop at low, got %d-%d", low, high)
+	}
+	w.SetFocusedTag(&low)
+	frame(nil)
+	key(sys.KeyEnd)
+	key(sys.KeyRight)
+	if low != 20 {
+		t.Errorf("Low should not pass high, got %d", low)
+	}
+	w.SetFocusedTag(&high)
+	frame(nil)
+	key(sys.KeyPageUp)
+	if high != 30 {
+		t.Errorf("Expected PageUp to add 10 steps of 1 to the high value, got %d", high)
+	}
+
+	// Vertical slider, where the top is the maximum
+	lv := accessNode(w, sys.RoleSlider, "Level")
+	if lv == nil || lv.Bounds.H != 150 {
+		t.Fatalf("Expected a vertical slider with the height 150")
+	}
+	cx := lv.Bounds.X + lv.Bounds.W/2
+	drag(cx, lv.Bounds.Y+lv.Bounds.H/2+10, cx, lv.Bounds.Y)
+	if level != 1 {
+		t.Errorf("Expected the maximum after dragging to the top, got %v", level)
+	}
+	key(sys.KeyDown)
+	key(sys.KeyDown)
+	key(sys.KeyDown)
+	if level != 0.7 || accessNode(w, sys.RoleSlider, "Level").Value != "0.7" {
+		t.Errorf("Expected 0.7 after three steps down, got %v", level)
+	}
+}
@@ -0,0 +1,133 @@
+package test
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/wid"
+)
+
+func TestSpin(t *testing.T) {
+	slog.Info("TestSpin")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	wid.ClearBuffers()
+	w := sys.CreateWindow(0, 0, 400, 200, "Test", 1, 1.0)
+	w.Focused = true
+	temp := 21.5
+	freq := 100
+	changes := 0
+	form := wid.Col(nil,
+		wid.Spin(&temp, 5, 30, 0.5, "Temperature", "°C", func() { changes++ }, nil),
+		wid.Spin(&freq, 0, 1000, 10, "Frequency", "kHz", nil, nil),
+	)
+	frame := func(input func()) {
+		w.StartFrame()
+		if input != nil {
+			input()
+		}
+		wid.Show(form)
+		w.EndFrame()
+	}
+	key := func(k sys.Key) {
+		frame(func() { w.SimKey(k, 0) })
+		frame(nil)
+	}
+	typeText := func(s string) {
+		frame(func() {
+			w.SimKey(sys.KeyEnd, 0)
+			for range 8 {
+				w.SimKey(sys.KeyBackspace, 0)
+			}
+			for _, r := range s {
+				w.SimChar(r)
+			}
+		})
+		frame(nil)
+	}
+	node := func(name string) *sys.AccessNode {
+		return accessNode(w, sys.RoleSpinButton, name)
+	}
+	frame(nil)
+	if n := node("Temperature"); n == nil || n.Value != "21.5 °C" || n.State&sys.StateInvalid != 0 {
+		t.Fatalf("Expected a spin box showing 21.5 °C, got %v", n)
+	}
+
+	// Step with the keys
+	w.SetFocusedTag(&temp)
+	frame(nil)
+	key(sys.KeyUp)
+	key(sys.KeyPageDown)
+	if temp != 17 || node("Temperature").Value != "17.0 °C" || changes != 2 {
+		t.Errorf("Expected 17.0 after Up and PageDown, which is 10 steps, got %v", temp)
+	}
+	key(sys.KeyPageDown)
+	key(sys.KeyPageDown)
+	key(sys.KeyPageDown)
+	if temp != 5 {
+		t.Errorf("The value should stop at the minimum, got %v", temp)
+	}
+
+	// Values out of range or not parsable are shown as invalid, and are not used
+	typeText("40")
+	if n := node("Temperature"); n.Value != "40 °C" || n.State&sys.StateInvalid == 0 {
+		t.Errorf("Expected 40 to be marked as invalid, got %v", n)
+	}
+	key(sys.KeyEnter)
+	if temp != 5 || node("Temperature").State&sys.StateInvalid == 0 {
+		t.Errorf("An invalid value should not be used, got %v", temp)
+	}
+	key(sys.KeyEscape)
+	if n := node("Temperature"); n.Value != "5.0 °C" || n.State&sys.StateInvalid != 0 {
+		t.Errorf("Escape should revert the text, got %v", n.Value)
+	}
+	typeText("25 °C")
+	key(sys.KeyEnter)
+	if temp != 25 {
+		t.Errorf("Expected the typed value 25, got %v", temp)
+	}
+	typeText("2x")
+	if node("Temperature").State&sys.StateInvalid == 0 {
+		t.Errorf("Expected 2x to be invalid")
+	}
+	// Moving the focus reverts the invalid text
+	w.SetFocusedTag(&freq)
+	frame(nil)
+	frame(nil)
+	if n := node("Temperature"); temp != 25 || n.Value != "25.0 °C" {
+		t.Errorf("Expected the text to be reverted to 25.0, got %v", n.Value)
+	}
+	typeText("12.5")
+	if node("Frequency").State&sys.StateInvalid == 0 {
+		t.Errorf("Integer spin boxes should not accept decimals")
+	}
+	key(sys.KeyEscape)
+
+	// Mouse wheel and step buttons
+	r := node("Frequency").Bounds
+	frame(func() {
+		w.SimPos(r.X+10, r.Y+5)
+		w.SimScroll(0, 1, 0)
+	})
+	if freq != 110 {
+		t.Errorf("Expected the wheel to step up to 110, got %d", freq)
+	}
+	w.LeftBtnUpTime = time.Time{}
+	frame(func() { w.SimLeftBtnPress(r.X+r.W-5, r.Y+r.H-4) })
+	frame(func() { w.SimLeftBtnRelease(r.X+r.W-5, r.Y+r.H-4) })
+	if freq != 100 {
+		t.Errorf("Expected the down button to step to 100, got %d", freq)
+	}
+
+	// Values set by the application are shown when the spin box is not focused
+	w.SetFocusedTag(&temp)
+	freq = 500
+	frame(nil)
+	if node("Frequency").Value != "500 kHz" {
+		t.Errorf("Expected the new value to be shown, got %s", node("Frequency").Value)
+	}
+}
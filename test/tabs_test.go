@@ -0,0 +1,92 @@
+package test
+
+import (
+	"log/slog"
+	"strconv"
+	"testing"
+
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/wid"
+)
+
+func TestTabs(t *testing.T) {
+	slog.Info("TestTabs")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 300, 200, "Test", 1, 1.0)
+	w.Focused = true
+	var names [8]string
+	var lines [8][]wid.Wid
+	var tabs []wid.Tab
+	for i := range names {
+		for j := range 20 {
+			lines[i] = append(lines[i], wid.Label("Line "+strconv.Itoa(j), nil))
+		}
+		page := wid.Col(nil, append([]wid.Wid{wid.Edit(&names[i], "Name", nil, nil)}, lines[i]...)...)
+		tabs = append(tabs, wid.Tab{Title: "Page " + strconv.Itoa(i), Content: page, Closable: i > 0})
+	}
+	state := &wid.TabsState{}
+	state.OnClose = func(i int) {
+		tabs = append(tabs[:i], tabs[i+1:]...)
+	}
+	frame := func(f func()) {
+		w.StartFrame()
+		f()
+		wid.Show(wid.Tabs(state, tabs, nil))
+		w.EndFrame()
+	}
+	tab := func(title string) *sys.AccessNode {
+		return accessNode(w, sys.RoleTab, title)
+	}
+	frame(func() {})
+	if tab("Page 0") == nil || tab("Page 0").State&sys.StateSelected == 0 {
+		t.Fatalf("First tab should be active")
+	}
+
+	// Scroll the first page, and switch with Ctrl+Tab from the edit
+	w.SetFocusedTag(&names[0])
+	frame(func() {
+		w.SimPos(150, 150)
+		w.SimScroll(0, -3, 0)
+	})
+	for range 20 {
+		frame(func() {})
+	}
+	if accessNode(w, sys.RoleLabel, "Line 0").Bounds.Y > 0 {
+		t.Fatalf("The first page should be scrolled")
+	}
+	pos := state.Active
+	frame(func() { w.SimKey(sys.KeyTab, sys.ModControl) })
+	if state.Active != pos+1 || !w.At(state) {
+		t.Errorf("Ctrl+Tab should show the next tab and focus the strip, got %d", state.Active)
+	}
+	frame(func() { w.SimKey(sys.KeyTab, sys.ModControl|sys.ModShift) })
+	if state.Active != 0 {
+		t.Errorf("Ctrl+Shift+Tab should show the previous tab, got %d", state.Active)
+	}
+	frame(func() {})
+	if accessNode(w, sys.RoleLabel, "Line 0").Bounds.Y > 0 {
+		t.Errorf("The scroll position of the first page should be kept")
+	}
+
+	// The strip scrolls to show the active tab
+	for range 7 {
+		frame(func() { w.SimKey(sys.KeyRight, 0) })
+	}
+	frame(func() {})
+	if r := tab("Page 7").Bounds; state.Active != 7 || r.X+r.W > 300 || r.X < 0 {
+		t.Errorf("The last tab should be active and visible, got %d at %v", state.Active, r)
+	}
+
+	// Close the active last tab
+	r := tab("Page 7").Bounds
+	x, y := r.X+r.W-10, r.Y+r.H/2
+	frame(func() { w.SimLeftBtnPress(x, y) })
+	frame(func() { w.SimLeftBtnRelease(x, y) })
+	frame(func() {})
+	if len(tabs) != 7 || state.Active != 6 || tab("Page 7") != nil {
+		t.Errorf("Closing the last tab should show the previous one, got %d tabs, active %d", len(tabs), state.Active)
+	}
+}
@@ -0,0 +1,101 @@
+package test
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/wid"
+)
+
+func TestTimers(t *testing.T) {
+	slog.Info("TestTimers")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 200, 100, "Test", 1, 1.0)
+	oldMin, oldMax := sys.MinFrameDelay, sys.MaxFrameDelay
+	sys.MinFrameDelay, sys.MaxFrameDelay = 0, 5*time.Second
+	defer func() { sys.MinFrameDelay, sys.MaxFrameDelay = oldMin, oldMax }()
+
+	fired, ticks := 0, 0
+	sys.AfterFunc(30*time.Millisecond, func() { fired++ })
+	every := sys.Every(10*time.Millisecond, func() { ticks++ })
+	start := time.Now()
+	for fired == 0 && time.Since(start) < time.Second {
+		sys.PollEvents()
+	}
+	if fired != 1 || time.Since(start) < 30*time.Millisecond || time.Since(start) > 500*time.Millisecond {
+		t.Errorf("AfterFunc should fire once after 30ms, fired=%d after %v", fired, time.Since(start))
+	}
+	if ticks < 2 || !every.Stop() || every.Stop() {
+		t.Errorf("Every should fire repeatedly until stopped, ticks=%d", ticks)
+	}
+
+	// Animation frames are drawn without waiting
+	w.AnimateUntil(time.Now().Add(50 * time.Millisecond))
+	start = time.Now()
+	sys.PollEvents()
+	if !w.Animating() || time.Since(start) > 20*time.Millisecond {
+		t.Errorf("PollEvents should not wait while animating")
+	}
+	time.Sleep(60 * time.Millisecond)
+	if w.Animating() {
+		t.Errorf("Animation should have ended")
+	}
+
+	// With MaxFrameDelay 0 and no timers or animations, PollEvents waits until it is woken
+	sys.PollEvents()
+	sys.MaxFrameDelay = 0
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		w.Invalidate()
+	}()
+	start = time.Now()
+	sys.PollEvents()
+	if time.Since(start) < 90*time.Millisecond {
+		t.Errorf("PollEvents should wait for Invalidate when idle, waited %v", time.Since(start))
+	}
+}
+
+func TestCursorBlink(t *testing.T) {
+	slog.Info("TestCursorBlink")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 600, 70, "Test", 1, 1.0)
+	w.Focused = true
+	oldMin, oldMax, oldFreq := sys.MinFrameDelay, sys.MaxFrameDelay, sys.BlinkFrequency
+	// PollEvents must return also when the blinking has stopped and the application is idle
+	sys.MinFrameDelay, sys.MaxFrameDelay, sys.BlinkFrequency = 0, 50*time.Millisecond, 25
+	defer func() { sys.MinFrameDelay, sys.MaxFrameDelay, sys.BlinkFrequency = oldMin, oldMax, oldFreq }()
+	value := "abc"
+	other := 0
+	frame := func() {
+		w.StartFrame()
+		wid.Display(w, 10, 10, 570, wid.Edit(&value, "Test", nil, nil))
+		w.EndFrame()
+	}
+	w.SetFocusedTag(&value)
+	frame()
+	if !sys.BlinkState.Load() {
+		t.Errorf("Cursor should be visible when the edit gets focus")
+	}
+	sys.PollEvents()
+	if sys.BlinkState.Load() {
+		t.Errorf("Cursor should blink while the edit is focused")
+	}
+	// Move focus away, and the blinking stops
+	w.SetFocusedTag(&other)
+	frame()
+	sys.PollEvents()
+	sys.PollEvents()
+	state := sys.BlinkState.Load()
+	sys.PollEvents()
+	if !state || sys.BlinkState.Load() != state {
+		t.Errorf("Cursor should stop blinking when no edit is focused")
+	}
+}
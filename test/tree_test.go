@@ -0,0 +1,107 @@
+package test
+
+import (
+	"log/slog"
+	"strconv"
+	"testing"
+
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/wid"
+)
+
+func TestTree(t *testing.T) {
+	slog.Info("TestTree")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 400, 300, "Test", 1, 1.0)
+	w.Focused = true
+	loads := 0
+	state := &wid.TreeState{MultiSelect: true}
+	state.Load = func(n *wid.TreeNode) []*wid.TreeNode {
+		loads++
+		children := make([]*wid.TreeNode, 10000)
+		for i := range children {
+			children[i] = &wid.TreeNode{Text: n.Text + "." + strconv.Itoa(i), Leaf: true}
+		}
+		return children
+	}
+	var roots []*wid.TreeNode
+	for i := range 10 {
+		roots = append(roots, &wid.TreeNode{Text: "Node " + strconv.Itoa(i)})
+	}
+	tree := wid.Tree(state, roots, nil)
+	frame := func(key sys.Key, mods sys.ModifierKey) {
+		w.StartFrame()
+		if key != 0 {
+			w.SimKey(key, mods)
+		}
+		wid.Show(tree)
+		w.EndFrame()
+	}
+	w.SetFocusedTag(state)
+	frame(0, 0)
+	if len(w.AccessTree().Root.Children) != 10 || state.Current() != roots[0] {
+		t.Fatalf("Expected 10 rows with the cursor on the first")
+	}
+	// Expand all the root nodes, giving 100010 rows
+	for _, n := range roots {
+		state.Expand(n)
+	}
+	frame(sys.KeyDown, 0)
+	if loads != 10 || state.Current() != roots[0].Children[0] || !state.Current().Selected() {
+		t.Errorf("Down should select the first child, got %v", state.Current())
+	}
+	if n := len(w.AccessTree().Root.Children); n > 40 {
+		t.Errorf("Only the visible rows should be drawn, got %d", n)
+	}
+	frame(sys.KeyEnd, 0)
+	if state.Current() != roots[9].Children[9999] || state.Ypos <= 0 {
+		t.Errorf("End should move to the last node and scroll, got %v", state.Current().Text)
+	}
+	nodes := w.AccessTree().Root.Children
+	last := nodes[len(nodes)-1]
+	if last.Name != "Node 9.9999" || last.State&sys.StateFocused == 0 || last.State&sys.StateSelected == 0 {
+		t.Errorf("The last node should be visible, focused and selected, got %+v", last)
+	}
+
+	// Left goes to the parent, and collapses it
+	frame(sys.KeyLeft, 0)
+	frame(sys.KeyLeft, 0)
+	if state.Current() != roots[9] || roots[9].Expanded {
+		t.Errorf("Left should go to the parent and collapse it")
+	}
+	frame(sys.KeyRight, 0)
+	if !roots[9].Expanded || loads != 10 {
+		t.Errorf("Right should expand without loading again")
+	}
+
+	// Multi selection
+	frame(sys.KeyHome, 0)
+	frame(sys.KeyDown, sys.ModShift)
+	frame(sys.KeyDown, sys.ModShift)
+	if len(state.Selection()) != 3 {
+		t.Errorf("Expected 3 selected nodes, got %d", len(state.Selection()))
+	}
+	frame(sys.KeyDown, sys.ModControl)
+	frame(sys.KeySpace, 0)
+	if len(state.Selection()) != 4 || !roots[0].Children[2].Selected() {
+		t.Errorf("Expected 4 selected nodes, got %d", len(state.Selection()))
+	}
+
+	// Clicking the triangle collapses the node
+	first := w.AccessTree().Root.Children[0]
+	x, y := first.Bounds.X+4, first.Bounds.Y+first.Bounds.H/2
+	w.SimLeftBtnPress(x, y)
+	frame(0, 0)
+	w.SimLeftBtnRelease(x, y)
+	frame(0, 0)
+	frame(0, 0)
+	if roots[0].Expanded || len(w.AccessTree().Root.Children) < 3 {
+		t.Errorf("Clicking the triangle should collapse the node")
+	}
+	if w.AccessTree().Root.Children[1].Name != "Node 1" {
+		t.Errorf("Expected Node 1 after the collapsed node, got %s", w.AccessTree().Root.Children[1].Name)
+	}
+}
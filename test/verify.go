@@ -46,3 +46,11 @@ func VerifyScreen(t *testing.T, win *sys.Window, testName string, w float32, h f
 		t.Error(testName+".png image difference was", diff)
 	}
 }
+
+// referenceScale sets the scaling of the headless monitor to 150%, as used when making the
+// reference images for tests without NoScaling. It is restored when the test ends.
+func referenceScale(t *testing.T) {
+	old := sys.HeadlessScale
+	sys.HeadlessScale = 1.5
+	t.Cleanup(func() { sys.HeadlessScale = old })
+}
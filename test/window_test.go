@@ -0,0 +1,60 @@
+package test
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/jkvatne/jkvgui/f32"
+	"github.com/jkvatne/jkvgui/gpu"
+	"github.com/jkvatne/jkvgui/sys"
+)
+
+func TestWindowState(t *testing.T) {
+	slog.Info("TestWindowState")
+	sys.Init()
+	defer sys.Shutdown()
+	sys.NoScaling = true
+	slog.SetLogLoggerLevel(slog.LevelError)
+	w := sys.CreateWindow(0, 0, 400, 200, "Test", 1, 1.0)
+	var changes []sys.WindowState
+	w.OnStateChange = func(from, to sys.WindowState) {
+		changes = append(changes, to)
+	}
+	if w.State() != sys.Normal {
+		t.Errorf("New window should be Normal, is %s", w.State())
+	}
+	w.Maximize()
+	w.Maximize()
+	w.Minimize()
+	w.Restore()
+	w.Fullscreen(1)
+	w.Restore()
+	expected := []sys.WindowState{sys.Maximized, sys.Minimized, sys.Normal, sys.Fullscreen, sys.Normal}
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, changes)
+		}
+	}
+
+	if w.Title() != "Test" {
+		t.Errorf("Title should default to the name, got %s", w.Title())
+	}
+	w.SetTitle("New title")
+	if w.Title() != "New title" || w.Name != "Test" {
+		t.Errorf("SetTitle failed")
+	}
+	w.SetIcon(gpu.Home, f32.Blue)
+	w.SetSizeLimits(100, 100, 0, 0)
+
+	var width, height float32
+	w.OnResize = func(w, h float32) {
+		width, height = w, h
+	}
+	w.HandleSize(300, 150)
+	if width != 300 || height != 150 {
+		t.Errorf("Expected resize to 300x150, got %0.0fx%0.0f", width, height)
+	}
+}
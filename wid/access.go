@@ -0,0 +1,26 @@
+package wid
+
+import "github.com/jkvatne/jkvgui/sys"
+
+// accessName is the name reported to screen readers, the label or else the hint
+func accessName(label, hint string) string {
+	if label == "" {
+		return hint
+	}
+	return label
+}
+
+// accessState is the state of a widget that can get focus unless disabled
+func accessState(disabled bool) sys.AccessState {
+	if disabled {
+		return sys.StateDisabled
+	}
+	return sys.StateFocusable
+}
+
+func checked(on bool) sys.AccessState {
+	if on {
+		return sys.StateChecked
+	}
+	return 0
+}
@@ -129,11 +129,7 @@ func IsKeyClick(ctx Ctx) bool {
 	if ctx.Win.SuppressEvents {
 		return false
 	}
-	if ctx.Win.LastKey == sys.KeyEnter || ctx.Win.LastKey == sys.KeyKPEnter || ctx.Win.LastKey == sys.KeySpace {
-		ctx.Win.LastKey = 0
-		return true
-	}
-	return false
+	return ctx.Win.TakeKey(sys.KeyEnter, sys.KeyKPEnter, sys.KeySpace)
 }
 
 func Btn(text string, ic *gpu.Icon, action func(), style *BtnStyle, hint string) Wid {
@@ -218,6 +214,9 @@ func Btn(text string, ic *gpu.Icon, action func(), style *BtnStyle, hint string)
 		// Draw text
 		f.DrawText(ctx.Win.Gd, textRect.X, textRect.Y+f.Baseline, fg, 0, gpu.LTR, text)
 
+		ctx.Win.AddAccessNode(sys.AccessNode{Role: sys.RoleButton, Name: accessName(text, hint),
+			State: accessState(style.Disabled()), Bounds: btnOutline, Tag: action})
+
 		// Show debug rectangles
 		if *DebugWidgets {
 			ctx.Win.Gd.OutlinedRect(ctx.Rect, 0.5, f32.Red)
@@ -6,6 +6,7 @@ import (
 
 	"github.com/jkvatne/jkvgui/f32"
 	"github.com/jkvatne/jkvgui/gpu"
+	"github.com/jkvatne/jkvgui/sys"
 )
 
 // CachedScrollState is a ScrollState with additional data
@@ -35,6 +36,13 @@ func scrollDebug(msg string, args ...any) {
 	}
 }
 
+// ClearCache removes all cached widgets, so they are read again when drawn.
+// It must be called when the widgets returned by the read function would change.
+func (s *CachedScrollState) ClearCache() {
+	s.cache = nil
+	s.cacheStart = 0
+}
+
 // getCachedWidget implements a cache of widget pointers
 func getCachedWidget(s *CachedScrollState, idx int) Wid {
 	s.dbTotalCount = s.dbCount()
@@ -82,7 +90,7 @@ func getCachedWidget(s *CachedScrollState, idx int) Wid {
 		}
 		s.cacheStart = s.cacheStart - cnt
 		s.cache = append(w, s.cache...)
-		s.cache = s.cache[:s.cacheMaxSize]
+		s.cache = s.cache[:min(len(s.cache), s.cacheMaxSize)]
 		dbDebug("Fill cache front  ", "idx", idx, "cacheStart", s.cacheStart, "size", len(s.cache), "cnt", cnt)
 
 	}
@@ -143,6 +151,8 @@ func drawCached(ctx Ctx, state *CachedScrollState) []Dim {
 	// just to give a better estimate of the total list size Ymax
 	var i int
 	n := 0
+	ctx.Win.PushNavGroup(sys.NavVertical, 0)
+	defer ctx.Win.PopNavGroup()
 	for i = state.Npos; sumH < ctx.Rect.H+100; i++ {
 		w := getCachedWidget(state, i)
 		// if getCachedWidget returns nil, it indicates the end of the element list.
@@ -152,6 +162,7 @@ func drawCached(ctx Ctx, state *CachedScrollState) []Dim {
 		}
 		n++
 		// Do drawing and save the widget dimensions.
+		ctx.Win.NavChild(i)
 		dim := w(ctx0)
 		dims = append(dims, dim)
 		// Move down to next element
@@ -4,6 +4,7 @@ import (
 	"github.com/jkvatne/jkvgui/f32"
 	"github.com/jkvatne/jkvgui/gpu"
 	"github.com/jkvatne/jkvgui/gpu/font"
+	"github.com/jkvatne/jkvgui/sys"
 	"github.com/jkvatne/jkvgui/theme"
 )
 
@@ -73,6 +74,8 @@ func Checkbox(label string, state *bool, action func(), style *CbStyle, hint str
 		}
 		f.DrawText(ctx.Win.Gd, iconRect.X+fontHeight*6/5, ctx.Rect.Y+baseline, style.Role.Fg(), 0, gpu.LTR, label)
 		DrawDebuggingInfo(ctx, iconRect, iconRect, ctx.Rect)
+		ctx.Win.AddAccessNode(sys.AccessNode{Role: sys.RoleCheckBox, Name: accessName(label, hint),
+			State: accessState(false) | checked(*state), Bounds: extRect, Tag: state})
 
 		return Dim{W: ctx.Rect.W, H: ctx.Rect.H, Baseline: ctx.Baseline}
 	}
@@ -2,6 +2,7 @@ package wid
 
 import (
 	"github.com/jkvatne/jkvgui/f32"
+	"github.com/jkvatne/jkvgui/sys"
 	"github.com/jkvatne/jkvgui/theme"
 )
 
@@ -69,14 +70,17 @@ func Col(style *ContainerStyle, widgets ...Wid) Wid {
 		ctx0.Rect = ctx0.Rect.Inset(style.InsidePadding, 0)
 		ctx0.Mode = RenderChildren
 		ctx0.Baseline = 0
+		ctx.Win.PushNavGroup(sys.NavVertical, 0)
 		for i, w := range widgets {
 			if h[i] < 0 {
 				h[i] = 0
 			}
 			ctx0.Rect.H = h[i]
+			ctx.Win.NavChild(i)
 			dims[i] = w(ctx0)
 			ctx0.Rect.Y += h[i]
 		}
+		ctx.Win.PopNavGroup()
 		return Dim{W: ctx.W, H: sumH, Baseline: 0}
 	}
 }
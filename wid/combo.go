@@ -167,21 +167,19 @@ func Combo(value any, list []string, label string, style *ComboStyle) Wid {
 			EditMouseHandler(ctx, &state.EditState, valueRect, f, value)
 
 			if state.expanded {
-				if ctx.Win.LastKey == sys.KeyDown {
+				if ctx.Win.TakeKey(sys.KeyDown) {
 					state.index = min(state.index+1, len(list)-1)
-				} else if ctx.Win.LastKey == sys.KeyUp {
+				} else if ctx.Win.TakeKey(sys.KeyUp) {
 					state.index = max(state.index-1, 0)
-				} else if ctx.Win.LastKey == sys.KeyEnter || ctx.Win.LastKey == sys.KeyKPEnter {
+				} else if ctx.Win.TakeKey(sys.KeyEnter, sys.KeyKPEnter) {
 					setValue(ctx, state.index, state, list, value)
-					ctx.Win.LastKey = 0
-				} else if ctx.Win.LastKey == sys.KeyEscape {
+				} else if ctx.Win.TakeKey(sys.KeyEscape) {
 					slog.Debug("Combo: Esc key caused combo list to collapse")
 					state.expanded = false
 				}
 
 				// This function is run after all other drawing commands
 				dropDownBox := func() {
-					state.ScrollState.Dragging = state.ScrollState.Dragging && ctx.Win.LeftBtnDown()
 					lineHeight := fontHeight + style.InsidePadding.T + style.InsidePadding.B
 					// Find the number of visible lines
 					AvailableLinesBelow := int((ctx.Win.HeightDp - frameRect.Y - frameRect.H) / lineHeight)
@@ -269,7 +267,7 @@ func Combo(value any, list []string, label string, style *ComboStyle) Wid {
 				if !style.NotEditable {
 					EditText(ctx, &state.EditState, nil)
 				}
-				if ctx.Win.LastKey == sys.KeyEnter {
+				if ctx.Win.TakeKey(sys.KeyEnter, sys.KeyKPEnter) {
 					if state.expanded {
 						setValue(ctx, state.index, state, list, value)
 					} else {
@@ -337,6 +335,15 @@ func Combo(value any, list []string, label string, style *ComboStyle) Wid {
 		// Draw debugging rectangles if wid.DebugWidgets is true
 		DrawDebuggingInfo(ctx, labelRect, valueRect, ctx.Rect)
 
+		as := accessState(style.Disabled()) | sys.StateExpandable
+		if state.expanded {
+			as |= sys.StateExpanded
+		}
+		if !style.NotEditable && !style.Disabled() {
+			as |= sys.StateEditable
+		}
+		ctx.Win.AddAccessNode(sys.AccessNode{Role: sys.RoleComboBox, Name: label, Value: state.Buffer.String(),
+			State: as, Bounds: frameRect, Tag: value})
 		return dim
 	}
 }
@@ -0,0 +1,42 @@
+package wid
+
+import (
+	"github.com/jkvatne/jkvgui/f32"
+	"github.com/jkvatne/jkvgui/theme"
+)
+
+type DropStyle struct {
+	HoverRole    theme.UIRole
+	BorderWidth  float32
+	CornerRadius float32
+	HoverAlpha   float32
+}
+
+var DefaultDrop = DropStyle{
+	HoverRole:    theme.Primary,
+	BorderWidth:  2,
+	CornerRadius: 4,
+	HoverAlpha:   0.08,
+}
+
+// DropTarget draws the widget and calls the handler with the paths of the files
+// dropped on it. The widget is highlighted while the mouse is above it.
+func DropTarget(handler func(paths []string), style *DropStyle, widget Wid) Wid {
+	Default(&style, &DefaultDrop)
+	return func(ctx Ctx) Dim {
+		dim := widget(ctx)
+		if ctx.Mode != RenderChildren {
+			return dim
+		}
+		r := f32.Rect{X: ctx.Rect.X, Y: ctx.Rect.Y, W: dim.W, H: dim.H}
+		if paths := ctx.Win.Dropped(r); paths != nil && handler != nil {
+			handler(paths)
+			ctx.Win.Invalidate()
+		}
+		if ctx.Win.Hovered(r) {
+			c := style.HoverRole.Bg()
+			ctx.Win.Gd.RoundedRect(r, style.CornerRadius, style.BorderWidth, c.MultAlpha(style.HoverAlpha), c)
+		}
+		return dim
+	}
+}
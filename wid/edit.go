@@ -3,6 +3,7 @@ package wid
 import (
 	"fmt"
 	"log/slog"
+	"math"
 	"strconv"
 	"sync"
 
@@ -30,6 +31,9 @@ type EditStyle struct {
 	Dp                 int
 	ReadOnly           bool
 	Disabler           *bool
+	// ErrorColor is used for the background and text when the typed value is not valid
+	ErrorColor       theme.UIRole
+	ErrorBorderColor theme.UIRole
 }
 
 var DefaultEdit = EditStyle{
@@ -46,19 +50,23 @@ var DefaultEdit = EditStyle{
 	LabelRightAdjust:   true,
 	LabelSpacing:       2,
 	Dp:                 2,
+	ErrorColor:         theme.ErrorContainer,
+	ErrorBorderColor:   theme.Error,
 }
 
 const GridBorderWidth = 0.0
 
 var GridEdit = EditStyle{
-	FontNo:        gpu.Normal12,
-	EditSize:      1.0,
-	Color:         theme.PrimaryContainer,
-	BorderColor:   theme.Transparent,
-	InsidePadding: f32.Padding{L: 2, T: 1, R: 2, B: 1},
-	CursorWidth:   1,
-	BorderWidth:   GridBorderWidth,
-	Dp:            2,
+	FontNo:           gpu.Normal12,
+	EditSize:         1.0,
+	Color:            theme.PrimaryContainer,
+	BorderColor:      theme.Transparent,
+	InsidePadding:    f32.Padding{L: 2, T: 1, R: 2, B: 1},
+	CursorWidth:      1,
+	BorderWidth:      GridBorderWidth,
+	Dp:               2,
+	ErrorColor:       theme.ErrorContainer,
+	ErrorBorderColor: theme.Error,
 }
 
 type EditState struct {
@@ -77,6 +85,13 @@ var (
 	StateMapMutex sync.RWMutex
 )
 
+// Shortcuts used by the editors
+var (
+	CopyShortcut  = sys.Shortcut{Key: sys.KeyC, Char: 'c', Mods: sys.ModControl}
+	CutShortcut   = sys.Shortcut{Key: sys.KeyX, Char: 'x', Mods: sys.ModControl}
+	PasteShortcut = sys.Shortcut{Key: sys.KeyV, Char: 'v', Mods: sys.ModControl}
+)
+
 func (s *EditStyle) Disabled() bool {
 	return s.Disabler != nil && *s.Disabler == true
 }
@@ -138,6 +153,64 @@ func DrawCursor(ctx Ctx, style *EditStyle, state *EditState, valueRect f32.Rect,
 	}
 }
 
+// editColors returns the text, background and border colors of an editor.
+// The error colors are used when err is not nil.
+func editColors(style *EditStyle, state *EditState, err error) (fg, bg, border f32.Color) {
+	fg = style.Color.Fg()
+	bg = f32.Transparent
+	border = style.BorderColor.Bg()
+	if err != nil {
+		fg = style.ErrorColor.Fg()
+		bg = style.ErrorColor.Bg()
+		border = style.ErrorBorderColor.Bg()
+	} else if state.hovered {
+		bg = fg.WithAlpha(0.05)
+	}
+	if style.Disabled() {
+		fg = fg.Mute(0.3)
+	}
+	return fg, bg, border
+}
+
+// drawEditText draws the label, the selected text, the text in the buffer with the color fg,
+// and the blinking cursor when the editor is focused.
+func drawEditText(ctx Ctx, style *EditStyle, state *EditState, focused bool, label string, labelRect, valueRect f32.Rect, fg f32.Color) {
+	f := font.Get(style.FontNo)
+	// Draw label if it exists
+	if label != "" {
+		lfg := style.Color.Fg()
+		if style.LabelRightAdjust {
+			dx := max(0.0, labelRect.W-f.Width(label)-style.LabelSpacing)
+			f.DrawText(ctx.Win.Gd, labelRect.X+dx, valueRect.Y+f.Baseline, lfg, labelRect.W, gpu.LTR, label)
+		} else {
+			f.DrawText(ctx.Win.Gd, labelRect.X, valueRect.Y+f.Baseline, lfg, labelRect.W, gpu.LTR, label)
+		}
+	}
+
+	// Draw selected rectangle
+	if focused && state.SelStart != state.SelEnd {
+		if state.SelStart > state.SelEnd {
+			slog.Error("SelStart>SelEnd!")
+		} else {
+			r := valueRect
+			r.W = f.Width(state.Buffer.Slice(state.SelStart, state.SelEnd))
+			r.X += f.Width(state.Buffer.Slice(0, state.SelStart))
+			ctx.Win.Gd.SolidRect(r, theme.PrimaryContainer.Bg())
+		}
+	}
+
+	// Draw value
+	f.DrawText(ctx.Win.Gd, valueRect.X, valueRect.Y+f.Baseline, fg, valueRect.W, gpu.LTR, state.Buffer.String())
+
+	// Draw cursor
+	if focused && !style.Disabled() {
+		DrawCursor(ctx, style, state, valueRect, f)
+		if !ctx.Win.Blinking.Load() {
+			ctx.Win.Blinking.Store(true)
+		}
+	}
+}
+
 // CalculateRects returns frameRect, valueRect, labelRect based on available space in r
 func CalculateRects(hasLabel bool, style *EditStyle, r f32.Rect) (dim, frameRect, valueRect, labelRect f32.Rect) {
 	_, py := f32.TotalPadding(style.InsidePadding, style.OutsidePadding, style.BorderWidth)
@@ -196,18 +269,37 @@ func ClearBuffers() {
 	StateMap = make(map[any]*EditState)
 }
 
+// EditText handles all key and char events received since the last frame.
+// The events used by the editor are consumed, the others are left for other widgets.
 func EditText(ctx Ctx, state *EditState, action func()) {
-	if ctx.Win.LastRune != 0 {
+	handled := false
+	for _, e := range ctx.Win.KeyEvents() {
+		if editKey(ctx, state, e, action) {
+			e.Consume()
+			handled = true
+		}
+	}
+	if handled {
+		ctx.Win.LastKey = 0
+		ctx.Win.LastRune = 0
+		ctx.Win.Invalidate()
+	}
+}
+
+// editKey updates the edit state for one key or char event, and returns true if the event was used.
+func editKey(ctx Ctx, state *EditState, e *sys.Event, action func()) bool {
+	if e.Kind == sys.CharEvent {
 		p1 := min(state.SelStart, state.SelEnd, state.Buffer.RuneCount())
 		p2 := min(max(state.SelStart, state.SelEnd), state.Buffer.RuneCount())
 		s1 := state.Buffer.Slice(0, p1)
 		s2 := state.Buffer.Slice(p2, state.Buffer.RuneCount())
-		state.Buffer.Init(s1 + string(ctx.Win.LastRune) + s2)
-		ctx.Win.LastRune = 0
+		state.Buffer.Init(s1 + string(e.Rune) + s2)
 		state.SelStart++
 		state.SelEnd = state.SelStart
 		state.modified = true
-	} else if ctx.Win.LastKey == sys.KeyBackspace {
+	} else if !e.Typed() {
+		return false
+	} else if e.Key == sys.KeyBackspace {
 		if state.SelStart == state.SelEnd && state.SelStart > 0 {
 			// Delete single char backwards
 			state.SelStart--
@@ -223,7 +315,7 @@ func EditText(ctx Ctx, state *EditState, action func()) {
 			state.SelEnd = state.SelStart
 		}
 		state.modified = true
-	} else if ctx.Win.LastKey == sys.KeyDelete {
+	} else if e.Key == sys.KeyDelete {
 		s1 := state.Buffer.Slice(0, max(state.SelStart, 0))
 		if state.SelEnd == state.SelStart {
 			state.SelEnd++
@@ -232,30 +324,30 @@ func EditText(ctx Ctx, state *EditState, action func()) {
 		state.Buffer.Init(s1 + s2)
 		state.SelEnd = state.SelStart
 		state.modified = true
-	} else if ctx.Win.LastKey == sys.KeyRight && ctx.Win.LastMods == sys.ModShift {
+	} else if e.Key == sys.KeyRight && e.Mods == sys.ModShift {
 		state.SelEnd = min(state.SelEnd+1, state.Buffer.RuneCount())
-	} else if ctx.Win.LastKey == sys.KeyLeft && ctx.Win.LastMods == sys.ModShift {
+	} else if e.Key == sys.KeyLeft && e.Mods == sys.ModShift {
 		state.SelStart = max(0, state.SelStart-1)
-	} else if ctx.Win.LastKey == sys.KeyLeft {
+	} else if e.Key == sys.KeyLeft {
 		state.SelStart = max(0, state.SelStart-1)
 		state.SelEnd = state.SelStart
-	} else if ctx.Win.LastKey == sys.KeyRight {
+	} else if e.Key == sys.KeyRight {
 		state.SelStart = min(state.SelStart+1, state.Buffer.RuneCount())
 		state.SelEnd = state.SelStart
-	} else if ctx.Win.LastKey == sys.KeyEnd {
+	} else if e.Key == sys.KeyEnd {
 		state.SelEnd = state.Buffer.RuneCount()
-		if ctx.Win.LastMods != sys.ModShift {
+		if e.Mods != sys.ModShift {
 			state.SelStart = state.SelEnd
 		}
-	} else if ctx.Win.LastKey == sys.KeyHome {
+	} else if e.Key == sys.KeyHome {
 		state.SelStart = 0
-		if ctx.Win.LastMods != sys.ModShift {
+		if e.Mods != sys.ModShift {
 			state.SelEnd = 0
 		}
-	} else if ctx.Win.LastKey == sys.KeyC && ctx.Win.LastMods == sys.ModControl {
+	} else if CopyShortcut.Matches(e) {
 		// Copy to clipboard
 		sys.SetClipboardString(state.Buffer.Slice(state.SelStart, state.SelEnd))
-	} else if ctx.Win.LastKey == sys.KeyX && ctx.Win.LastMods == sys.ModControl {
+	} else if CutShortcut.Matches(e) {
 		// Copy to clipboard
 		sys.SetClipboardString(state.Buffer.Slice(state.SelStart, state.SelEnd))
 		s1 := state.Buffer.Slice(0, max(state.SelStart, 0))
@@ -265,23 +357,20 @@ func EditText(ctx Ctx, state *EditState, action func()) {
 		s2 := state.Buffer.Slice(min(state.SelEnd, state.Buffer.RuneCount()), state.Buffer.RuneCount())
 		state.Buffer.Init(s1 + s2)
 		state.SelEnd = state.SelStart
-	} else if ctx.Win.LastKey == sys.KeyV && ctx.Win.LastMods == sys.ModControl {
+	} else if PasteShortcut.Matches(e) {
 		// Insert from clipboard
 		s1 := state.Buffer.Slice(0, state.SelStart)
 		s2 := state.Buffer.Slice(min(state.SelEnd, state.Buffer.RuneCount()), state.Buffer.RuneCount())
 		s3, _ := sys.GetClipboardString()
 		state.Buffer.Init(s1 + s3 + s2)
 		state.modified = true
-	} else if ctx.Win.LastKey == sys.KeyEnter || ctx.Win.LastKey == sys.KeyKPEnter {
-		if action != nil {
-			updateValue(&ctx, state)
-			action()
-		}
-	}
-	if ctx.Win.LastKey != 0 {
-		ctx.Win.LastKey = 0
-		ctx.Win.Invalidate()
+	} else if (e.Key == sys.KeyEnter || e.Key == sys.KeyKPEnter) && action != nil {
+		updateValue(&ctx, state)
+		action()
+	} else {
+		return false
 	}
+	return true
 }
 
 func EditMouseHandler(ctx Ctx, state *EditState, valueRect f32.Rect, f *font.Font, value any) {
@@ -342,30 +431,48 @@ func DrawDebuggingInfo(ctx Ctx, labelRect f32.Rect, valueRect f32.Rect, WidgetRe
 	}
 }
 
+// editRange returns the range used to check the text typed for a number,
+// and false if the value is not a number.
+func editRange(value any) (numberRange, bool) {
+	switch value.(type) {
+	case *int:
+		return numberRange{min: math.MinInt, max: math.MaxInt, integer: true}, true
+	case *float32:
+		return numberRange{min: -math.MaxFloat32, max: math.MaxFloat32}, true
+	case *float64:
+		return numberRange{min: -math.MaxFloat64, max: math.MaxFloat64}, true
+	}
+	return numberRange{}, false
+}
+
+// updateValue sets the value from the text typed. Numbers that are not valid are not used,
+// and the text is reverted to the current value.
 func updateValue(ctx *Ctx, state *EditState) {
 	state.modified = false
 	ctx.Win.Mutex.Lock()
 	defer ctx.Win.Mutex.Unlock()
+	r, isNumber := editRange(state.value)
+	x, err := r.parse(state.Buffer.String(), "")
+	if isNumber && err != nil {
+		slog.Debug("Edit: invalid value reverted", "error", err)
+	}
 	switch v := state.value.(type) {
 	case *int:
-		n, err := strconv.Atoi(state.Buffer.String())
 		if err == nil {
-			*v = n
+			*v = int(x)
 		}
 		state.Buffer.Init(fmt.Sprintf("%d", *v))
 	case *string:
 		*v = state.Buffer.String()
 		state.Buffer.Init(fmt.Sprintf("%s", *v))
 	case *float32:
-		f, err := strconv.ParseFloat(state.Buffer.String(), 64)
 		if err == nil {
-			*v = float32(f)
+			*v = float32(x)
 		}
 		state.Buffer.Init(strconv.FormatFloat(float64(*v), 'f', state.dp, 32))
 	case *float64:
-		f, err := strconv.ParseFloat(state.Buffer.String(), 64)
 		if err == nil {
-			*v = f
+			*v = x
 		}
 		state.Buffer.Init(strconv.FormatFloat(*v, 'f', state.dp, 64))
 	}
@@ -402,8 +509,8 @@ func Edit(value any, label string, action func(), style *EditStyle) Wid {
 
 	// Pre-calculate some values
 	f := font.Get(style.FontNo)
-	fg := style.Color.Fg()
 	bw := style.BorderWidth
+	nr, isNumber := editRange(value)
 
 	return func(ctx Ctx) Dim {
 		// dim := style.Dim(ctx, f)
@@ -438,49 +545,33 @@ func Edit(value any, label string, action func(), style *EditStyle) Wid {
 			state.SelStart = cnt
 		}
 
-		// Draw frame around value with gray background when hovered
-		bg := f32.Transparent
-		if state.hovered {
-			bg = fg.WithAlpha(0.05)
+		// Numbers that are not valid are shown with the error colors
+		var err error
+		if isNumber {
+			_, err = nr.parse(state.Buffer.String(), "")
 		}
-		ctx.Win.Gd.RoundedRect(frameRect, style.BorderCornerRadius, bw, bg, style.BorderColor.Bg())
-
-		// Draw label if it exists
-		if label != "" {
-			if style.LabelRightAdjust {
-				dx := max(0.0, labelRect.W-f.Width(label)-style.LabelSpacing)
-				f.DrawText(ctx.Win.Gd, labelRect.X+dx, valueRect.Y+f.Baseline, fg, labelRect.W, gpu.LTR, label)
-			} else {
-				f.DrawText(ctx.Win.Gd, labelRect.X, valueRect.Y+f.Baseline, fg, labelRect.W, gpu.LTR, label)
-			}
-		}
-
-		// Draw selected rectangle
-		if focused && state.SelStart != state.SelEnd {
-			if state.SelStart > state.SelEnd {
-				slog.Error("SelStart>SelEnd!")
-			} else {
-				r := valueRect
-				r.W = f.Width(state.Buffer.Slice(state.SelStart, state.SelEnd))
-				r.X += f.Width(state.Buffer.Slice(0, state.SelStart))
-				ctx.Win.Gd.SolidRect(r, theme.PrimaryContainer.Bg())
-			}
-		}
-
-		// Draw value
-		f.DrawText(ctx.Win.Gd, valueRect.X, valueRect.Y+f.Baseline, fg, valueRect.W, gpu.LTR, state.Buffer.String())
-
-		// Draw cursor
-		if !style.ReadOnly && ctx.Win.At(value) {
-			DrawCursor(ctx, style, state, valueRect, f)
-			if !ctx.Win.Blinking.Load() {
-				ctx.Win.Blinking.Store(true)
-			}
+		fg, bg, border := editColors(style, state, err)
+		if err != nil && state.hovered {
+			Hint(ctx, err.Error(), value)
 		}
+		// Draw frame around value with gray background when hovered
+		ctx.Win.Gd.RoundedRect(frameRect, style.BorderCornerRadius, bw, bg, border)
+		drawEditText(ctx, style, state, focused, label, labelRect, valueRect, fg)
 
 		// Draw debugging rectangles if gpu.DebugWidgets is true
 		DrawDebuggingInfo(ctx, labelRect, valueRect, ctx.Rect)
 
+		as := accessState(style.Disabled())
+		if style.ReadOnly {
+			as &^= sys.StateFocusable
+		} else if !style.Disabled() {
+			as |= sys.StateEditable
+		}
+		if err != nil {
+			as |= sys.StateInvalid
+		}
+		ctx.Win.AddAccessNode(sys.AccessNode{Role: sys.RoleEdit, Name: label, Value: state.Buffer.String(),
+			State: as, Bounds: frameRect, Tag: value})
 		return dim
 	}
 }
@@ -0,0 +1,447 @@
+package wid
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"github.com/jkvatne/jkvgui/f32"
+	"github.com/jkvatne/jkvgui/gpu"
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/theme"
+)
+
+// CellKind selects the widget used for the cells of a grid column
+type CellKind int
+
+const (
+	// CellLabel shows the value as read-only text
+	CellLabel CellKind = iota
+	// CellEdit is an Edit, for *string, *int, *float32 and *float64 values
+	CellEdit
+	// CellCheckbox is a Checkbox, for *bool values
+	CellCheckbox
+	// CellCombo is a Combo with the column's Items, for *int and *string values
+	CellCombo
+)
+
+// GridColumn defines one column of a Grid.
+type GridColumn[T any] struct {
+	Title string
+	// Get returns a pointer to the field shown in the column, like func(p *person) any { return &p.Name }
+	Get  func(row *T) any
+	Kind CellKind
+	// Width is the width in dp, or a fraction of the free width when less than or equal to 1.0.
+	// Columns with zero width share the rest of the free width equally.
+	Width float32
+	// Items are the choices in a CellCombo column
+	Items []string
+	// Less is used when sorting by the column. When nil, the values returned by Get are compared.
+	Less func(a, b *T) bool
+	// NoSort disables sorting when the header is clicked
+	NoSort bool
+}
+
+// GridState is the state of a Grid, and must be kept between frames.
+type GridState struct {
+	CachedScrollState
+	widths []float32
+	// dragged is true for the columns the user has changed the width of
+	dragged []bool
+	// gridW is the width the other columns were fitted to
+	gridW       float32
+	sortActions []func()
+	clicked     int
+	// sortCol and selected are the column/row numbers plus one, so the zero value is none.
+	sortCol    int
+	descending bool
+	selected   int
+	first      any
+	count      int
+}
+
+// DataGridStyle is the style of a Grid, including the styles of the cell widgets.
+type DataGridStyle struct {
+	HeaderRole   theme.UIRole
+	EvenRole     theme.UIRole
+	OddRole      theme.UIRole
+	SelectedRole theme.UIRole
+	BorderRole   theme.UIRole
+	BorderWidth  float32
+	// HandleWidth is the width of the area around the column borders that can be dragged
+	HandleWidth    float32
+	MinColumnWidth float32
+	Header         BtnStyle
+	Label          LabelStyle
+	Edit           EditStyle
+	Checkbox       CbStyle
+	Combo          ComboStyle
+	Scroll         ScrollStyle
+}
+
+var DefaultDataGrid = DataGridStyle{
+	HeaderRole:     theme.TertiaryContainer,
+	EvenRole:       theme.SecondaryContainer,
+	OddRole:        theme.PrimaryContainer,
+	SelectedRole:   theme.SurfaceContainer,
+	BorderRole:     theme.Outline,
+	BorderWidth:    0.5,
+	HandleWidth:    6,
+	MinColumnWidth: 20,
+	Header:         *Header,
+	Label:          LabelStyle{FontNo: gpu.Normal12, Role: theme.OnSurface, Padding: f32.Padding{L: 2, T: 1, R: 2, B: 1}},
+	Edit:           GridEdit,
+	Checkbox:       GridCheckBox,
+	Combo:          GridCombo,
+	Scroll:         ScrollStyle{ScrollbarWidth: 10, MinThumbHeight: 15, TrackAlpha: 0.15, NormalAlpha: 0.4, HoverAlpha: 0.8, ScrollerMargin: 1, ThumbCornerRadius: 3, ScrollFactor: 0.2},
+}
+
+// Selected returns the index of the selected row, or -1 if no row is selected.
+func (state *GridState) Selected() int {
+	return state.selected - 1
+}
+
+// Select will select the row with the given index. Use -1 to clear the selection.
+func (state *GridState) Select(row int) {
+	state.selected = max(row, -1) + 1
+}
+
+// SortColumn returns the column the rows are sorted by, or -1 if not sorted.
+func (state *GridState) SortColumn() (col int, descending bool) {
+	return state.sortCol - 1, state.descending
+}
+
+// Grid is a table showing the rows in data, with one cell widget for each column.
+// Clicking a header sorts the rows by that column, and clicking it again reverses the order.
+// The column borders in the header can be dragged to change the widths, and clicking a row selects it.
+// Only the visible rows are made and drawn, so the data can be large.
+// The columns are fitted to style.Scroll.ContentWidth, or the visible width if that is larger.
+// When they are wider than the grid, the header and the rows are scrolled horizontally together.
+func Grid[T any](state *GridState, data *[]T, columns []GridColumn[T], style *DataGridStyle) Wid {
+	f32.ExitIf(state == nil, "Grid state must not be nil")
+	if style == nil {
+		style = &DefaultDataGrid
+	}
+	if len(state.sortActions) != len(columns) {
+		state.sortActions = make([]func(), len(columns))
+		for i := range columns {
+			state.sortActions[i] = func() { state.clicked = i + 1 }
+		}
+	}
+	rowWidget := func(i int) Wid {
+		if i >= len(*data) {
+			return nil
+		}
+		cells := make([]Wid, len(columns))
+		for c := range columns {
+			cells[c] = gridCell(&columns[c], &(*data)[i], style)
+		}
+		return gridRow(state, style, cells, i)
+	}
+	rows := CashedScroller(&state.CachedScrollState, &style.Scroll, rowWidget, func() int { return len(*data) })
+
+	return func(ctx Ctx) Dim {
+		if ctx.Mode != RenderChildren {
+			return Dim{W: style.Scroll.Width, H: style.Scroll.Height}
+		}
+		// The cached rows point into the data, so they must be made again if it has been reallocated.
+		var first any
+		if len(*data) > 0 {
+			first = &(*data)[0]
+		}
+		if len(*data) != state.count || first != state.first {
+			state.count, state.first = len(*data), first
+			state.ClearCache()
+		}
+		barW := style.Scroll.ScrollbarWidth
+		gridWidths(state, columns, max(ctx.W-barW, style.Scroll.ContentWidth), style.MinColumnWidth)
+		contentW := float32(0)
+		for _, w := range state.widths {
+			contentW += w
+		}
+		// The vertical scrollbar is to the right of the content
+		state.Xmax = contentW + barW
+		state.Xpos = max(0, min(state.Xpos, state.Xmax-ctx.W))
+		HorScrollbarUserInput(ctx, &state.ScrollState, &style.Scroll)
+
+		header := make([]Wid, len(columns))
+		for i := range columns {
+			var icon *gpu.Icon
+			var action func()
+			if !columns[i].NoSort {
+				icon = gpu.NavigationUnfoldMore
+				action = state.sortActions[i]
+			}
+			if state.sortCol == i+1 && state.descending {
+				icon = gpu.NavigationArrowDownward
+			} else if state.sortCol == i+1 {
+				icon = gpu.NavigationArrowUpward
+			}
+			header[i] = Btn(columns[i].Title, icon, action, &style.Header, "")
+		}
+		h, baseline := gridRowHeight(ctx, state.widths, header)
+		hdrCtx := ctx
+		hdrCtx.Rect.H = h
+		hdrCtx.Baseline = baseline
+		ctx.Win.Gd.RoundedRect(hdrCtx.Rect, 0, style.BorderWidth, style.HeaderRole.Bg(), style.BorderRole.Bg())
+		// The borders are handled before the buttons, and the buttons are kept clear of them,
+		// so dragging a border does not sort the rows.
+		dragColumnBorders(hdrCtx, state, style)
+		ctx.Win.Gd.Clip(hdrCtx.Rect)
+		drawGridCells(gridScrolled(hdrCtx, state), state.widths, header, style, style.HandleWidth/2)
+		gpu.NoClip()
+		if state.clicked > 0 {
+			sortGrid(state, *data, columns, state.clicked-1)
+			state.clicked = 0
+		}
+
+		rowCtx := ctx
+		rowCtx.Rect.Y += h
+		rowCtx.Rect.H -= h
+		if state.Xmax > ctx.W {
+			// Make room for the horizontal scrollbar below the rows
+			rowCtx.Rect.H -= barW
+		}
+		rows(rowCtx)
+		DrawHorScrollbar(ctx, &state.ScrollState, &style.Scroll)
+		return Dim{W: ctx.W, H: ctx.H}
+	}
+}
+
+// gridWidths sets the widths of the columns, fitted to the width w. When w changes, the
+// columns are fitted again, except the ones the user has dragged, which keep their width.
+func gridWidths[T any](state *GridState, columns []GridColumn[T], w float32, minWidth float32) {
+	if len(state.widths) == len(columns) && state.gridW == w {
+		return
+	}
+	if len(state.widths) != len(columns) {
+		state.widths = make([]float32, len(columns))
+		state.dragged = make([]bool, len(columns))
+	}
+	state.gridW = w
+	free := w
+	emptyCount := 0
+	for i, c := range columns {
+		if state.dragged[i] {
+			free -= state.widths[i]
+		} else if c.Width > 1.0 {
+			free -= c.Width
+		} else if c.Width == 0 {
+			emptyCount++
+		}
+	}
+	free = max(free, 0)
+	rest := free
+	for i, c := range columns {
+		if state.dragged[i] {
+			continue
+		}
+		if c.Width > 1.0 {
+			state.widths[i] = c.Width
+		} else if c.Width > 0 {
+			state.widths[i] = free * c.Width
+			rest -= state.widths[i]
+		}
+	}
+	for i, c := range columns {
+		if c.Width == 0 && !state.dragged[i] {
+			state.widths[i] = max(rest, 0) / float32(emptyCount)
+		}
+		state.widths[i] = max(state.widths[i], minWidth)
+	}
+}
+
+// gridScrolled returns the context for the cells of a row, moved by the horizontal scrolling
+func gridScrolled(ctx Ctx, state *GridState) Ctx {
+	ctx.Rect.X -= state.Xpos
+	ctx.Rect.W = max(ctx.Rect.W, state.Xmax)
+	return ctx
+}
+
+// dragColumnBorders lets the user change the column widths by dragging the borders in the header.
+// Clicks on the borders are consumed, so they are not seen by the header buttons.
+func dragColumnBorders(ctx Ctx, state *GridState, style *DataGridStyle) {
+	x := ctx.Rect.X - state.Xpos
+	for i := range state.widths {
+		x += state.widths[i]
+		r := f32.Rect{X: x - style.HandleWidth/2, Y: ctx.Rect.Y, W: style.HandleWidth, H: ctx.Rect.H}
+		if r.X+r.W > ctx.Rect.X+ctx.Rect.W || r.X < ctx.Rect.X {
+			// Borders scrolled out of view can not be grabbed
+			r.W = 0
+		}
+		g := ctx.Win.Gesture(r, &state.widths[i])
+		if g.Delta.X != 0 {
+			state.widths[i] = max(state.widths[i]+g.Delta.X, style.MinColumnWidth)
+			state.dragged[i] = true
+			ctx.Win.Invalidate()
+		}
+		if g.Hovered || g.Dragging {
+			ctx.Win.SetCursor(sys.HResizeCursor)
+		}
+		_ = ctx.Win.LeftBtnClick(r)
+	}
+}
+
+// sortGrid sorts the rows by the given column. The selected row is kept selected.
+func sortGrid[T any](state *GridState, data []T, columns []GridColumn[T], col int) {
+	if state.sortCol == col+1 {
+		state.descending = !state.descending
+	} else {
+		state.sortCol, state.descending = col+1, false
+	}
+	c := &columns[col]
+	less := c.Less
+	if less == nil {
+		less = func(a, b *T) bool { return lessValue(c.Get(a), c.Get(b)) }
+	}
+	order := make([]int, len(data))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(i, j int) int {
+		a, b := &data[i], &data[j]
+		if state.descending {
+			a, b = b, a
+		}
+		if less(a, b) {
+			return -1
+		} else if less(b, a) {
+			return 1
+		}
+		return 0
+	})
+	sorted := make([]T, len(data))
+	selected := 0
+	for i, j := range order {
+		sorted[i] = data[j]
+		if j+1 == state.selected {
+			selected = i + 1
+		}
+	}
+	copy(data, sorted)
+	state.selected = selected
+	clearCellStates(data, columns)
+	state.ClearCache()
+}
+
+// clearCellStates removes the Edit and Combo states of the grid's cells. They are
+// stored by the value pointers, and would show other rows after sorting.
+func clearCellStates[T any](data []T, columns []GridColumn[T]) {
+	StateMapMutex.Lock()
+	defer StateMapMutex.Unlock()
+	for i := range data {
+		for c := range columns {
+			switch columns[c].Kind {
+			case CellEdit:
+				delete(StateMap, columns[c].Get(&data[i]))
+			case CellCombo:
+				delete(ComboStateMap, columns[c].Get(&data[i]))
+			}
+		}
+	}
+}
+
+// gridRowHeight returns the height and baseline of a row, given the column widths
+func gridRowHeight(ctx Ctx, widths []float32, cells []Wid) (h, baseline float32) {
+	ctx.Mode = CollectHeights
+	for i, cell := range cells {
+		ctx.Rect.W = widths[i]
+		dim := cell(ctx)
+		h = max(h, dim.H)
+		baseline = max(baseline, dim.Baseline)
+	}
+	return h, baseline
+}
+
+// drawGridCells draws the cells of one row, with vertical lines between them.
+// The cells are made narrower by inset on both sides.
+func drawGridCells(ctx Ctx, widths []float32, cells []Wid, style *DataGridStyle, inset float32) {
+	ctx.Mode = RenderChildren
+	ctx.Win.PushNavGroup(sys.NavHorizontal, 0)
+	for i, cell := range cells {
+		cellCtx := ctx
+		cellCtx.Rect.X += inset
+		cellCtx.Rect.W = max(0, widths[i]-2*inset)
+		ctx.Win.NavChild(i)
+		cell(cellCtx)
+		ctx.Rect.X += widths[i]
+		ctx.Win.Gd.VertLine(ctx.Rect.X, ctx.Rect.Y, ctx.Rect.Y+ctx.Rect.H, style.BorderWidth, style.BorderRole.Bg())
+	}
+	ctx.Win.PopNavGroup()
+}
+
+func gridRow(state *GridState, style *DataGridStyle, cells []Wid, row int) Wid {
+	return func(ctx Ctx) Dim {
+		h, baseline := gridRowHeight(ctx, state.widths, cells)
+		if ctx.Mode != RenderChildren {
+			return Dim{W: ctx.W, H: h, Baseline: baseline}
+		}
+		ctx.Rect.H = h
+		ctx.Baseline = baseline
+		if ctx.Win.LeftBtnPressed(ctx.Rect) {
+			state.selected = row + 1
+		}
+		role := style.OddRole
+		if state.selected == row+1 {
+			role = style.SelectedRole
+		} else if row%2 == 0 {
+			role = style.EvenRole
+		}
+		ctx.Win.Gd.RoundedRect(ctx.Rect, 0, style.BorderWidth, role.Bg(), style.BorderRole.Bg())
+		drawGridCells(gridScrolled(ctx, state), state.widths, cells, style, 0)
+		return Dim{W: ctx.W, H: h, Baseline: baseline}
+	}
+}
+
+// gridCell makes the widget for one cell
+func gridCell[T any](col *GridColumn[T], row *T, style *DataGridStyle) Wid {
+	value := col.Get(row)
+	switch col.Kind {
+	case CellEdit:
+		return Edit(value, "", nil, &style.Edit)
+	case CellCheckbox:
+		b, ok := value.(*bool)
+		if !ok {
+			f32.Exit(1, "Grid checkbox column with value that is not *bool")
+		}
+		return Checkbox("", b, nil, &style.Checkbox, "")
+	case CellCombo:
+		return Combo(value, col.Items, "", &style.Combo)
+	}
+	return func(ctx Ctx) Dim {
+		return Label(cellText(value), &style.Label)(ctx)
+	}
+}
+
+// cellText formats the value pointed to
+func cellText(value any) string {
+	switch v := value.(type) {
+	case *string:
+		return *v
+	case *int:
+		return strconv.Itoa(*v)
+	case *float32:
+		return strconv.FormatFloat(float64(*v), 'f', -1, 32)
+	case *float64:
+		return strconv.FormatFloat(*v, 'f', -1, 64)
+	}
+	return fmt.Sprint(reflect.Indirect(reflect.ValueOf(value)).Interface())
+}
+
+// lessValue compares the values pointed to by a and b
+func lessValue(a, b any) bool {
+	switch v := a.(type) {
+	case *string:
+		return *v < *b.(*string)
+	case *int:
+		return *v < *b.(*int)
+	case *float32:
+		return *v < *b.(*float32)
+	case *float64:
+		return *v < *b.(*float64)
+	case *bool:
+		return !*v && *b.(*bool)
+	}
+	return cellText(a) < cellText(b)
+}
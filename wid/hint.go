@@ -6,6 +6,7 @@ import (
 	"github.com/jkvatne/jkvgui/f32"
 	"github.com/jkvatne/jkvgui/gpu"
 	"github.com/jkvatne/jkvgui/gpu/font"
+	"github.com/jkvatne/jkvgui/sys"
 	"github.com/jkvatne/jkvgui/theme"
 )
 
@@ -39,6 +40,8 @@ func Hint(ctx Ctx, text string, tag any) {
 	}
 	if !gpu.TagsEqual(ctx.Win.CurrentHint.Tag, tag) {
 		ctx.Win.CurrentHint.T = time.Now()
+		// Make sure a frame is drawn when the hint should be shown
+		sys.AfterFunc(DefaultHintStyle.Delay, ctx.Win.Invalidate)
 	}
 	ctx.Win.CurrentHint.Text = text
 	ctx.Win.CurrentHint.Tag = tag
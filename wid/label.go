@@ -4,6 +4,7 @@ import (
 	"github.com/jkvatne/jkvgui/f32"
 	"github.com/jkvatne/jkvgui/gpu"
 	"github.com/jkvatne/jkvgui/gpu/font"
+	"github.com/jkvatne/jkvgui/sys"
 	"github.com/jkvatne/jkvgui/theme"
 )
 
@@ -166,6 +167,8 @@ func Label(text string, style *LabelStyle) Wid {
 				ctx.Win.Gd.HorLine(x, x+width, y, 1, f32.Blue)
 			}
 		}
+		ctx.Win.AddAccessNode(sys.AccessNode{Role: sys.RoleLabel, Name: text,
+			Bounds: f32.Rect{X: ctx.Rect.X, Y: ctx.Rect.Y, W: min(width, ctx.Rect.W), H: height}})
 		return Dim{W: width, H: height, Baseline: baseline}
 	}
 }
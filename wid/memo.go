@@ -2,10 +2,12 @@ package wid
 
 import (
 	"log/slog"
+	"strings"
 
 	"github.com/jkvatne/jkvgui/f32"
 	"github.com/jkvatne/jkvgui/gpu"
 	"github.com/jkvatne/jkvgui/gpu/font"
+	"github.com/jkvatne/jkvgui/sys"
 	"github.com/jkvatne/jkvgui/theme"
 )
 
@@ -112,6 +114,8 @@ func Memo(text *[]string, style *MemoStyle) Wid {
 		defer ctx.Win.Mutex.Unlock()
 		textLen := len(*text)
 		ctx0 := ctx
+		// The lines from Npos to shown are visible
+		shown := textLen
 
 		if state.Ypos >= state.Ymax+ctx.H {
 			state.AtEnd = true
@@ -141,6 +145,7 @@ func Memo(text *[]string, style *MemoStyle) Wid {
 				height = drawlines(ctx0, (*text)[i], Wmax, f, fg)
 				ctx0.Rect.Y += height
 				updateYmax(i, state, height)
+				shown = i + 1
 			}
 		}
 		gpu.NoClip()
@@ -150,6 +155,9 @@ func Memo(text *[]string, style *MemoStyle) Wid {
 			state.Nmax = textLen
 		}
 
+		ctx.Win.AddAccessNode(sys.AccessNode{Role: sys.RoleMemo, Value: strings.Join((*text)[min(state.Npos, shown):shown], "\n"),
+			State: sys.StateFocusable | sys.StateMultiLine, Bounds: ctx.Rect, Tag: state})
+
 		doScrolling(ctx, state, func(n int) float32 { return height })
 		DrawVertScrollbar(ctx, state, &style.ScrollStyle)
 		return Dim{W: ctx.W, H: ctx.H, Baseline: baseline}
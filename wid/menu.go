@@ -0,0 +1,612 @@
+package wid
+
+import (
+	"github.com/jkvatne/jkvgui/f32"
+	"github.com/jkvatne/jkvgui/gpu"
+	"github.com/jkvatne/jkvgui/gpu/font"
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/theme"
+)
+
+// MenuItem is one entry in a menu bar or a popup menu.
+// An item with Items opens a submenu instead of running an action.
+type MenuItem struct {
+	Text string
+	Icon *gpu.Icon
+	// Shortcut is the text shown to the right of the item, like "Ctrl+S".
+	// When Binding is set and Shortcut is empty, the binding's shortcut is shown.
+	Shortcut string
+	// Binding is the key binding running the same action. Its action is used when Action is nil.
+	Binding *sys.Binding
+	// Checked makes the item checkable. The value is toggled when the item is chosen.
+	Checked  *bool
+	Disabled bool
+	// Separator items are drawn as a horizontal line, and can not be chosen.
+	Separator bool
+	Items     []MenuItem
+	Action    func()
+}
+
+// MenuSeparator is a separator line between groups of items
+var MenuSeparator = MenuItem{Separator: true}
+
+// MenuState is the state of a MenuBar or a PopupMenu, and must be kept between frames.
+type MenuState struct {
+	// path is the index of the open item at each level. path[0] is the open menu in
+	// the bar, or 0 for a popup menu. The menu is closed when path is empty.
+	path []int
+	// hot is the highlighted item in each open popup, or -1
+	hot    []int
+	origin f32.Pos
+	titles []f32.Rect
+	mouse  f32.Pos
+	opened bool
+}
+
+// MenuStyle is the style of menu bars and popup menus
+type MenuStyle struct {
+	FontNo       int
+	BarRole      theme.UIRole
+	PopupRole    theme.UIRole
+	HotRole      theme.UIRole
+	BorderRole   theme.UIRole
+	BorderWidth  float32
+	CornerRadius float32
+	ShadowSize   float32
+	TitlePadding f32.Padding
+	ItemPadding  f32.Padding
+	// PopupPadding is the space above the first and below the last item in a popup
+	PopupPadding float32
+	// IconSize is the size of icons, check marks and submenu arrows, relative to the font height
+	IconSize float32
+	Spacing  float32
+	// ShortcutSpacing is the minimum space between the item text and the shortcut text
+	ShortcutSpacing float32
+	SeparatorHeight float32
+}
+
+var DefaultMenu = &MenuStyle{
+	FontNo:          gpu.Normal12,
+	BarRole:         theme.SurfaceContainer,
+	PopupRole:       theme.Surface,
+	HotRole:         theme.PrimaryContainer,
+	BorderRole:      theme.Outline,
+	BorderWidth:     1,
+	CornerRadius:    4,
+	ShadowSize:      5,
+	TitlePadding:    f32.Padding{L: 8, T: 4, R: 8, B: 4},
+	ItemPadding:     f32.Padding{L: 8, T: 3, R: 8, B: 3},
+	PopupPadding:    4,
+	IconSize:        1.0,
+	Spacing:         6,
+	ShortcutSpacing: 24,
+	SeparatorHeight: 7,
+}
+
+// menuPopup is the layout of one open popup
+type menuPopup struct {
+	items []MenuItem
+	rect  f32.Rect
+	rows  []f32.Rect
+}
+
+// Open is true while a menu is shown
+func (state *MenuState) Open() bool {
+	return len(state.path) > 0
+}
+
+// Close will hide all open menus
+func (state *MenuState) Close() {
+	state.path = state.path[:0]
+	state.hot = state.hot[:0]
+}
+
+// Popup opens the menu of a PopupMenu at the given position
+func (state *MenuState) Popup(x, y float32) {
+	state.path = append(state.path[:0], 0)
+	state.hot = append(state.hot[:0], -1)
+	state.origin = f32.Pos{X: x, Y: y}
+	state.opened = true
+}
+
+// openTitle opens menu number i in the menu bar
+func (state *MenuState) openTitle(i int, hot int) {
+	if i >= len(state.titles) {
+		return
+	}
+	r := state.titles[i]
+	state.path = append(state.path[:0], i)
+	state.hot = append(state.hot[:0], hot)
+	state.origin = f32.Pos{X: r.X, Y: r.Y + r.H}
+}
+
+// openSub opens the submenu of item j in popup number i
+func (state *MenuState) openSub(i, j, hot int) {
+	state.path = append(state.path[:i+1], j)
+	state.hot = append(state.hot[:i+1], hot)
+}
+
+// closeFrom closes the submenus of popup number i
+func (state *MenuState) closeFrom(i int) {
+	state.path = state.path[:i+1]
+	state.hot = state.hot[:i+1]
+}
+
+// shortcutText returns the text shown to the right of the item
+func (m *MenuItem) shortcutText() string {
+	if m.Shortcut == "" && m.Binding != nil {
+		return m.Binding.Shortcut.String()
+	}
+	return m.Shortcut
+}
+
+// selectable is true for items that can be highlighted and chosen
+func (m *MenuItem) selectable() bool {
+	return !m.Separator && !m.Disabled
+}
+
+// nextItem returns the next selectable item in the given direction, wrapping around, or -1.
+func nextItem(items []MenuItem, from int, step int) int {
+	n := len(items)
+	if from < 0 && step < 0 {
+		from = n
+	}
+	for k := 0; k < n; k++ {
+		from = ((from+step)%n + n) % n
+		if items[from].selectable() {
+			return from
+		}
+	}
+	return -1
+}
+
+// nextMenu returns the next menu in the bar that can be opened, or -1
+func nextMenu(menus []MenuItem, from int, step int) int {
+	for range menus {
+		from = nextItem(menus, from, step)
+		if from < 0 || len(menus[from].Items) > 0 {
+			return from
+		}
+	}
+	return -1
+}
+
+// activate closes the menu and runs the item's action
+func (state *MenuState) activate(win *sys.Window, m *MenuItem) {
+	state.Close()
+	if m.Checked != nil {
+		*m.Checked = !*m.Checked
+	}
+	if m.Action != nil {
+		m.Action()
+	} else if m.Binding != nil && m.Binding.Action != nil {
+		m.Binding.Action()
+	}
+	win.Invalidate()
+}
+
+// layout calculates the position of the open popups. Levels that are no longer valid,
+// because the menu items have changed, are closed.
+func (state *MenuState) layout(win *sys.Window, root []MenuItem, style *MenuStyle) []menuPopup {
+	f := font.Get(style.FontNo)
+	pad := style.ItemPadding
+	iconSize := f.Height * style.IconSize
+	rowHeight := f.Height + pad.T + pad.B
+	var popups []menuPopup
+	items := root
+	for i, k := range state.path {
+		if k < 0 || k >= len(items) || len(items[k].Items) == 0 || items[k].Disabled {
+			if i == 0 {
+				state.Close()
+			} else {
+				state.closeFrom(i - 1)
+			}
+			break
+		}
+		items = items[k].Items
+		// Find the width of the columns
+		lead, arrow := false, false
+		textW, shortcutW, h := float32(0), float32(0), 2*style.PopupPadding
+		for _, m := range items {
+			if m.Separator {
+				h += style.SeparatorHeight
+				continue
+			}
+			h += rowHeight
+			lead = lead || m.Icon != nil || m.Checked != nil
+			arrow = arrow || len(m.Items) > 0
+			textW = max(textW, f.Width(m.Text))
+			shortcutW = max(shortcutW, f.Width(m.shortcutText()))
+		}
+		w := pad.L + textW + pad.R
+		if lead {
+			w += iconSize + style.Spacing
+		}
+		if shortcutW > 0 {
+			w += style.ShortcutSpacing + shortcutW
+		}
+		if arrow {
+			w += style.Spacing + iconSize
+		}
+		// The first popup is placed at the origin, submenus to the right of the parent item,
+		// or to the left if there is no space on the right side.
+		r := f32.Rect{X: state.origin.X, Y: state.origin.Y, W: w, H: h}
+		if i > 0 {
+			parent := popups[i-1]
+			r.X = parent.rect.X + parent.rect.W
+			r.Y = parent.rows[k].Y - style.PopupPadding
+			if r.X+r.W > win.WidthDp {
+				r.X = parent.rect.X - r.W
+			}
+		}
+		r.X = max(0, min(r.X, win.WidthDp-r.W))
+		r.Y = max(0, min(r.Y, win.HeightDp-r.H))
+		p := menuPopup{items: items, rect: r}
+		y := r.Y + style.PopupPadding
+		for _, m := range items {
+			row := f32.Rect{X: r.X, Y: y, W: r.W, H: rowHeight}
+			if m.Separator {
+				row.H = style.SeparatorHeight
+			}
+			p.rows = append(p.rows, row)
+			y += row.H
+		}
+		popups = append(popups, p)
+	}
+	return popups
+}
+
+// menuMouse handles hovering and clicking in the open popups and the menu bar titles
+func (state *MenuState) menuMouse(win *sys.Window, root []MenuItem, popups []menuPopup, bar bool) {
+	moved := sys.HasMoved(win.MousePos(), state.mouse)
+	state.mouse = win.MousePos()
+	if state.opened {
+		// Ignore the click that opened the menu
+		state.opened = false
+		return
+	}
+	for i := len(popups) - 1; i >= 0; i-- {
+		p := popups[i]
+		if !win.Hovered(p.rect) {
+			continue
+		}
+		for j, r := range p.rows {
+			m := &p.items[j]
+			if !win.Hovered(r) || !m.selectable() {
+				continue
+			}
+			if moved && state.hot[i] != j {
+				state.hot[i] = j
+				state.closeFrom(i)
+				if len(m.Items) > 0 {
+					state.openSub(i, j, -1)
+				}
+				win.Invalidate()
+			}
+			if win.LeftBtnClick(r) {
+				if len(m.Items) > 0 {
+					state.openSub(i, j, -1)
+				} else {
+					state.activate(win, m)
+				}
+				win.Invalidate()
+			}
+		}
+		return
+	}
+	if bar {
+		for t, r := range state.titles {
+			if !win.Hovered(r) {
+				continue
+			}
+			if win.LeftBtnClick(r) && state.path[0] == t {
+				state.Close()
+				win.Invalidate()
+			} else if moved && state.path[0] != t && root[t].selectable() && len(root[t].Items) > 0 {
+				state.openTitle(t, -1)
+				win.Invalidate()
+			}
+			return
+		}
+	}
+	all := f32.Rect{W: win.WidthDp, H: win.HeightDp}
+	if win.LeftBtnClick(all) || win.RightBtnClick(all) {
+		state.Close()
+		win.Invalidate()
+	}
+}
+
+// menuKeys handles keyboard navigation in the open popups
+func (state *MenuState) menuKeys(win *sys.Window, root []MenuItem, popups []menuPopup, bar bool) {
+	if len(popups) == 0 {
+		return
+	}
+	i := len(popups) - 1
+	items := popups[i].items
+	hot := state.hot[i]
+	var m *MenuItem
+	if hot >= 0 && hot < len(items) {
+		m = &items[hot]
+	}
+	// switchTitle moves to the next or previous menu in the bar
+	switchTitle := func(step int) {
+		if t := nextMenu(root, state.path[0], step); t >= 0 {
+			state.openTitle(t, nextItem(root[t].Items, -1, 1))
+		}
+	}
+	switch {
+	case win.TakeKey(sys.KeyEscape):
+		if i > 0 {
+			state.closeFrom(i - 1)
+		} else {
+			state.Close()
+		}
+	case win.TakeKey(sys.KeyDown):
+		state.hot[i] = nextItem(items, hot, 1)
+	case win.TakeKey(sys.KeyUp):
+		state.hot[i] = nextItem(items, hot, -1)
+	case win.TakeKey(sys.KeyHome):
+		state.hot[i] = nextItem(items, -1, 1)
+	case win.TakeKey(sys.KeyEnd):
+		state.hot[i] = nextItem(items, -1, -1)
+	case win.TakeKey(sys.KeyRight):
+		if m != nil && len(m.Items) > 0 {
+			state.openSub(i, hot, nextItem(m.Items, -1, 1))
+		} else if bar {
+			switchTitle(1)
+		}
+	case win.TakeKey(sys.KeyLeft):
+		if i > 0 {
+			state.closeFrom(i - 1)
+		} else if bar {
+			switchTitle(-1)
+		}
+	case win.TakeKey(sys.KeyEnter, sys.KeyKPEnter, sys.KeySpace):
+		if m != nil && len(m.Items) > 0 {
+			state.openSub(i, hot, nextItem(m.Items, -1, 1))
+		} else if m != nil {
+			state.activate(win, m)
+		}
+	case bar && win.TakeKey(sys.KeyF10):
+		state.Close()
+	default:
+		return
+	}
+	win.Invalidate()
+}
+
+// drawMenus is called after all other widgets are drawn. It handles the input to the open menus,
+// and draws them on top of everything else. Input to the other widgets is suppressed while the menu is open.
+func (state *MenuState) drawMenus(ctx Ctx, root []MenuItem, style *MenuStyle, bar bool) {
+	if !state.Open() {
+		return
+	}
+	win := ctx.Win
+	state.menuMouse(win, root, state.layout(win, root, style), bar)
+	state.menuKeys(win, root, state.layout(win, root, style), bar)
+	popups := state.layout(win, root, style)
+	if !state.Open() {
+		return
+	}
+	f := font.Get(style.FontNo)
+	pad := style.ItemPadding
+	iconSize := f.Height * style.IconSize
+	name := root[state.path[0]].Text
+	for i, p := range popups {
+		if len(p.items) == 0 {
+			continue
+		}
+		win.Gd.Shade(p.rect, style.CornerRadius, f32.Shade, style.ShadowSize)
+		win.Gd.RoundedRect(p.rect, style.CornerRadius, style.BorderWidth, style.PopupRole.Bg(), style.BorderRole.Bg())
+		lead := false
+		for _, m := range p.items {
+			lead = lead || m.Icon != nil || m.Checked != nil
+		}
+		node := sys.AccessNode{Role: sys.RoleMenu, Name: name, State: sys.StateExpanded, Bounds: p.rect}
+		for j := range p.items {
+			m := &p.items[j]
+			r := p.rows[j]
+			if m.Separator {
+				win.Gd.HorLine(r.X+pad.L, r.X+r.W-pad.R, r.Y+r.H/2, style.BorderWidth, style.BorderRole.Bg())
+				continue
+			}
+			fg := style.PopupRole.Fg()
+			open := i+1 < len(state.path) && state.path[i+1] == j
+			if state.hot[i] == j || open {
+				hr := f32.Rect{X: r.X + style.BorderWidth, Y: r.Y, W: r.W - 2*style.BorderWidth, H: r.H}
+				win.Gd.SolidRect(hr, style.HotRole.Bg())
+				fg = style.HotRole.Fg()
+			}
+			if m.Disabled {
+				fg = fg.Mute(0.3)
+			}
+			iconY := r.Y + (r.H-iconSize)/2
+			x := r.X + pad.L
+			if lead {
+				icon := m.Icon
+				if m.Checked != nil {
+					icon = nil
+					if *m.Checked {
+						icon = gpu.NavigationCheck
+					}
+				}
+				if icon != nil {
+					win.Gd.DrawIcon(x, iconY, iconSize, icon, fg)
+				}
+				x += iconSize + style.Spacing
+			}
+			f.DrawText(win.Gd, x, r.Y+pad.T+f.Baseline, fg, 0, gpu.LTR, m.Text)
+			right := r.X + r.W - pad.R
+			if len(m.Items) > 0 {
+				win.Gd.DrawIcon(right-iconSize, iconY, iconSize, gpu.NavigationChevronRight, fg)
+			} else if s := m.shortcutText(); s != "" {
+				f.DrawText(win.Gd, right-f.Width(s), r.Y+pad.T+f.Baseline, fg.MultAlpha(0.7), 0, gpu.LTR, s)
+			}
+
+			role := sys.RoleMenuItem
+			as := accessState(m.Disabled)
+			if m.Checked != nil {
+				role = sys.RoleCheckMenuItem
+				as |= checked(*m.Checked)
+			}
+			if state.hot[i] == j {
+				as |= sys.StateSelected
+			}
+			if len(m.Items) > 0 {
+				as |= sys.StateExpandable
+				if open {
+					as |= sys.StateExpanded
+				}
+			}
+			node.Children = append(node.Children, &sys.AccessNode{Role: role, Name: m.Text, State: as, Bounds: r})
+		}
+		win.AddAccessNode(node)
+		if i+1 < len(state.path) {
+			name = p.items[state.path[i+1]].Text
+		}
+	}
+	win.SuppressEvents = true
+}
+
+// MenuBar is a horizontal bar with the titles of the given menus. Clicking a title, or pressing F10,
+// opens the menu below it. The menus are navigated with the arrow keys, Enter chooses the
+// highlighted item, and Escape or a click outside the menus closes them.
+// A menu without Items runs its action when the title is clicked.
+func MenuBar(state *MenuState, menus []MenuItem, style *MenuStyle) Wid {
+	f32.ExitIf(state == nil, "MenuBar state must not be nil")
+	if style == nil {
+		style = DefaultMenu
+	}
+	f := font.Get(style.FontNo)
+	pad := style.TitlePadding
+	iconSize := f.Height * style.IconSize
+	height := f.Height + pad.T + pad.B
+	widths := make([]float32, len(menus))
+	for i, m := range menus {
+		widths[i] = pad.L + f.Width(m.Text) + pad.R
+		if m.Icon != nil {
+			widths[i] += iconSize + style.Spacing
+		}
+	}
+
+	return func(ctx Ctx) Dim {
+		if ctx.Mode != RenderChildren {
+			return Dim{W: ctx.W, H: height, Baseline: pad.T + f.Baseline}
+		}
+		barRect := f32.Rect{X: ctx.X, Y: ctx.Y, W: ctx.W, H: height}
+		ctx.Win.Gd.SolidRect(barRect, style.BarRole.Bg())
+		state.titles = state.titles[:0]
+		barNode := sys.AccessNode{Role: sys.RoleMenuBar, Bounds: barRect}
+		x := ctx.X
+		for i := range menus {
+			m := &menus[i]
+			r := f32.Rect{X: x, Y: ctx.Y, W: widths[i], H: height}
+			x += widths[i]
+			state.titles = append(state.titles, r)
+			open := state.Open() && state.path[0] == i
+			fg := style.BarRole.Fg()
+			if open {
+				ctx.Win.Gd.SolidRect(r, style.HotRole.Bg())
+				fg = style.HotRole.Fg()
+			} else if !m.Disabled && ctx.Win.Hovered(r) {
+				ctx.Win.Gd.SolidRect(r, fg.MultAlpha(0.08))
+			}
+			if m.Disabled {
+				fg = fg.Mute(0.3)
+			}
+			tx := r.X + pad.L
+			if m.Icon != nil {
+				ctx.Win.Gd.DrawIcon(tx, r.Y+(r.H-iconSize)/2, iconSize, m.Icon, fg)
+				tx += iconSize + style.Spacing
+			}
+			f.DrawText(ctx.Win.Gd, tx, r.Y+pad.T+f.Baseline, fg, 0, gpu.LTR, m.Text)
+			if !m.Disabled && !state.Open() && ctx.Win.LeftBtnClick(r) {
+				if len(m.Items) > 0 {
+					state.openTitle(i, -1)
+					state.opened = true
+				} else {
+					state.activate(ctx.Win, m)
+				}
+				ctx.Win.Invalidate()
+			}
+			role := sys.RoleMenu
+			as := accessState(m.Disabled)
+			if len(m.Items) > 0 {
+				as |= sys.StateExpandable
+				if open {
+					as |= sys.StateExpanded
+				}
+			} else {
+				role = sys.RoleMenuItem
+			}
+			barNode.Children = append(barNode.Children, &sys.AccessNode{Role: role, Name: m.Text, State: as, Bounds: r})
+		}
+		if !state.Open() {
+			// Shift+F10 is left for the context menus
+			for _, e := range ctx.Win.KeyEvents() {
+				if e.Typed() && e.Key == sys.KeyF10 && e.Mods&(sys.ModShift|sys.ModControl|sys.ModAlt|sys.ModSuper) == 0 {
+					e.Consume()
+					if t := nextMenu(menus, -1, 1); t >= 0 {
+						state.openTitle(t, nextItem(menus[t].Items, -1, 1))
+						ctx.Win.Invalidate()
+					}
+					break
+				}
+			}
+		}
+		ctx.Win.AddAccessNode(barNode)
+		if state.Open() {
+			ctx.Win.Defer(func() { state.drawMenus(ctx, menus, style, true) })
+		}
+		return Dim{W: ctx.W, H: height, Baseline: pad.T + f.Baseline}
+	}
+}
+
+// PopupMenu draws the menu opened by MenuState.Popup on top of the other widgets.
+// It takes no space, and should be placed anywhere in the form.
+func PopupMenu(state *MenuState, items []MenuItem, style *MenuStyle) Wid {
+	f32.ExitIf(state == nil, "PopupMenu state must not be nil")
+	if style == nil {
+		style = DefaultMenu
+	}
+	root := []MenuItem{{Items: items}}
+	return func(ctx Ctx) Dim {
+		if ctx.Mode == RenderChildren && state.Open() {
+			ctx.Win.Defer(func() { state.drawMenus(ctx, root, style, false) })
+		}
+		return Dim{}
+	}
+}
+
+// ContextMenu draws the widget w, and opens the menu at the mouse position when w is right-clicked.
+// Shift+F10 opens the menu when the focus is inside w.
+func ContextMenu(state *MenuState, items []MenuItem, style *MenuStyle, w Wid) Wid {
+	popup := PopupMenu(state, items, style)
+	return func(ctx Ctx) Dim {
+		if ctx.Mode != RenderChildren {
+			return w(ctx)
+		}
+		ctx.Win.PushScope(state)
+		dim := w(ctx)
+		ctx.Win.PopScope()
+		if !state.Open() {
+			if ctx.Win.RightBtnClick(ctx.Rect) {
+				p := ctx.Win.MousePos()
+				state.Popup(p.X, p.Y)
+				ctx.Win.Invalidate()
+			} else if ctx.Win.FocusInScope(state) {
+				for _, e := range ctx.Win.KeyEvents() {
+					if e.Typed() && e.Key == sys.KeyF10 && e.Mods&sys.ModShift != 0 {
+						e.Consume()
+						state.Popup(ctx.X, ctx.Y+dim.H)
+						state.hot[0] = nextItem(items, -1, 1)
+						ctx.Win.Invalidate()
+						break
+					}
+				}
+			}
+		}
+		popup(ctx)
+		return dim
+	}
+}
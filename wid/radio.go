@@ -4,6 +4,7 @@ import (
 	"github.com/jkvatne/jkvgui/f32"
 	"github.com/jkvatne/jkvgui/gpu"
 	"github.com/jkvatne/jkvgui/gpu/font"
+	"github.com/jkvatne/jkvgui/sys"
 	"github.com/jkvatne/jkvgui/theme"
 )
 
@@ -48,6 +49,8 @@ func RadioButton(label string, value *string, key string, style *CbStyle) Wid {
 			ctx.Win.Gd.DrawIcon(iconRect.X, iconRect.Y-1, iconRect.H, gpu.RadioUnchecked, style.Role.Fg())
 		}
 		f.DrawText(ctx.Win.Gd, iconRect.X+fontHeight*6/5, ctx.Rect.Y+baseline, style.Role.Fg(), 0, gpu.LTR, label)
+		ctx.Win.AddAccessNode(sys.AccessNode{Role: sys.RoleRadioButton, Name: label,
+			State: accessState(false) | checked(*value == key), Bounds: extRect, Tag: value})
 
 		return Dim{W: ctx.Rect.W, H: ctx.Rect.H, Baseline: ctx.Baseline}
 	}
@@ -13,8 +13,9 @@ import (
 
 type ResizerState struct {
 	// Pos can be -W/2 to +W/2. Zero means divide in two equal parts.
-	pos      float32
-	dragging bool
+	pos float32
+	// StartPos is the mouse position while dragging.
+	// Deprecated: Use Window.Gesture to follow the mouse.
 	StartPos float32
 }
 
@@ -34,15 +35,16 @@ func VertResizer(state *ResizerState, style *ResizerStyle, widget1 Wid, widget2
 		if ctx.Mode != RenderChildren {
 			return Dim{W: ctx.W, H: ctx.H, Baseline: ctx.Baseline}
 		}
-		state.dragging = state.dragging && ctx.Win.LeftBtnDown()
-		if state.dragging {
+		spacerRect := f32.Rect{X: ctx.X + ctx.W/2 + state.pos, Y: ctx.Y, W: style.Width, H: ctx.H}
+		g := ctx.Win.Gesture(spacerRect, state)
+		if g.Dragging {
+			state.StartPos = ctx.Win.MousePos().X
+		}
+		if dx := g.Delta.X; dx != 0 {
 			// Mouse dragging divider
-			if dx := ctx.Win.MousePos().X - state.StartPos; dx != 0 {
-				state.pos = min(max(state.pos+dx, -ctx.W/2), ctx.W/2-style.Width)
-				ctx.Win.Invalidate()
-				slog.Debug("Drag", "dy", dx, "pos", state.pos, "ctx.W", ctx.W, "ctx.H", ctx.H)
-			}
-			state.StartPos = ctx.Win.StartDrag().X
+			state.pos = min(max(state.pos+dx, -ctx.W/2), ctx.W/2-style.Width)
+			ctx.Win.Invalidate()
+			slog.Debug("Drag", "dx", dx, "pos", state.pos, "ctx.W", ctx.W, "ctx.H", ctx.H)
 		}
 
 		ctx1 := ctx
@@ -50,17 +52,11 @@ func VertResizer(state *ResizerState, style *ResizerStyle, widget1 Wid, widget2
 		ctx1.W = ctx.W/2 + state.pos - style.Width/2
 		ctx2.W = ctx.W - ctx1.W - style.Width/2
 		ctx2.X = ctx.X + ctx.W/2 + state.pos + style.Width/2
-		spacerRect := f32.Rect{X: ctx2.X - style.Width/2, Y: ctx1.Y, W: style.Width, H: ctx.H}
+		spacerRect.X = ctx2.X - style.Width/2
 		widget1(ctx1)
 		widget2(ctx2)
 		ctx.Win.Gd.SolidRect(spacerRect, theme.SurfaceContainer.Fg())
-		// Start dragging if mouse pressed
-		if ctx.Win.LeftBtnPressed(spacerRect) && !state.dragging {
-			state.dragging = true
-			state.StartPos = ctx.Win.StartDrag().X
-			slog.Debug("Start drag", "pos", state.pos, "state.StartPos", state.StartPos)
-		}
-		if ctx.Win.MousePos().Inside(spacerRect) {
+		if g.Hovered || g.Dragging {
 			ctx.Win.SetCursor(sys.HResizeCursor)
 		}
 		return Dim{W: ctx.W, H: ctx.H, Baseline: ctx.Baseline}
@@ -76,36 +72,31 @@ func HorResizer(state *ResizerState, style *ResizerStyle, widget1 Wid, widget2 W
 		if ctx.Mode != RenderChildren {
 			return Dim{W: ctx.W, H: ctx.H, Baseline: ctx.Baseline}
 		}
-		state.dragging = state.dragging && ctx.Win.LeftBtnDown()
-		if state.dragging {
+		spacerRect := f32.Rect{X: ctx.X, Y: ctx.Y + ctx.H/2 + state.pos - style.Width/2, W: ctx.W, H: style.Width}
+		g := ctx.Win.Gesture(spacerRect, state)
+		if g.Dragging {
+			state.StartPos = ctx.Win.MousePos().Y
+		}
+		if dy := g.Delta.Y; dy != 0 {
 			// Mouse dragging divider
-			if dy := ctx.Win.MousePos().Y - state.StartPos; dy != 0 {
-				state.pos = min(max(state.pos+dy, -ctx.H/2), ctx.H/2-style.Width)
-				ctx.Win.Invalidate()
-				slog.Debug("Drag", "dy", dy, "pos", state.pos, "ctx.W", ctx.W, "ctx.H", ctx.H)
-			}
-			state.StartPos = ctx.Win.StartDrag().Y
+			state.pos = min(max(state.pos+dy, -ctx.H/2), ctx.H/2-style.Width)
+			ctx.Win.Invalidate()
+			slog.Debug("Drag", "dy", dy, "pos", state.pos, "ctx.W", ctx.W, "ctx.H", ctx.H)
 		}
 
 		ctx1 := ctx
 		ctx2 := ctx
 		ctx1.H = ctx.H/2 + state.pos - style.Width/2
 		ctx2.H = ctx.H - ctx1.H - style.Width/2
-		ctx2.Y = ctx.X + ctx.H/2 + state.pos + style.Width/2
-		spacerRect := f32.Rect{X: ctx.X, Y: ctx2.Y - style.Width, W: ctx.W, H: style.Width}
+		ctx2.Y = ctx.Y + ctx.H/2 + state.pos + style.Width/2
+		spacerRect.Y = ctx2.Y - style.Width
 		ctx.Win.Gd.Clip(ctx1.Rect)
 		widget1(ctx1)
 		ctx.Win.Gd.Clip(ctx2.Rect)
 		widget2(ctx2)
 		gpu.NoClip()
 		ctx.Win.Gd.SolidRect(spacerRect, theme.SurfaceContainer.Fg())
-		// Start dragging if mouse pressed
-		if ctx.Win.LeftBtnPressed(spacerRect) && !state.dragging {
-			state.dragging = true
-			state.StartPos = ctx.Win.StartDrag().Y
-			slog.Debug("Start drag", "pos", state.pos, "state.StartPos", state.StartPos)
-		}
-		if ctx.Win.MousePos().Inside(spacerRect) {
+		if g.Hovered || g.Dragging {
 			ctx.Win.SetCursor(sys.VResizeCursor)
 		}
 		return Dim{W: ctx.W, H: ctx.H, Baseline: ctx.Baseline}
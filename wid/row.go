@@ -2,6 +2,7 @@ package wid
 
 import (
 	"github.com/jkvatne/jkvgui/f32"
+	"github.com/jkvatne/jkvgui/sys"
 )
 
 func Row(style *ContainerStyle, widgets ...Wid) Wid {
@@ -76,8 +77,10 @@ func Row(style *ContainerStyle, widgets ...Wid) Wid {
 		ctx0.Rect.H = min(maxH, ctx0.Rect.H)
 		ctx.Win.Gd.RoundedRect(ctx.Rect, style.CornerRadius, style.BorderWidth, style.Role.Bg(), style.BorderRole.Bg())
 		sumW = 0.0
+		ctx.Win.PushNavGroup(sys.NavHorizontal, 0)
 		for i, widget := range widgets {
 			ctx0.Rect.W = w[i]
+			ctx.Win.NavChild(i)
 			dim := widget(ctx0)
 			sumW += dim.W
 			ctx0.Rect.X += w[i]
@@ -85,6 +88,7 @@ func Row(style *ContainerStyle, widgets ...Wid) Wid {
 				ctx.Win.Gd.VertLine(ctx0.Rect.X, ctx0.Rect.Y, ctx0.Rect.Y+ctx0.Rect.H, style.BorderWidth, style.BorderRole.Bg())
 			}
 		}
+		ctx.Win.PopNavGroup()
 		ctx.Win.Gd.RoundedRect(ctx.Rect, style.CornerRadius, style.BorderWidth, f32.Transparent, style.BorderRole.Bg())
 		return Dim{W: sumW, H: maxH, Baseline: maxB}
 	}
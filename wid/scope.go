@@ -0,0 +1,28 @@
+package wid
+
+// Scope draws the widget inside a focus scope with the given tag.
+// The tag should be a pointer. Shortcuts bound with this tag as scope are only active
+// when the focused widget is inside the scope.
+func Scope(tag any, widget Wid) Wid {
+	return func(ctx Ctx) Dim {
+		if ctx.Mode != RenderChildren {
+			return widget(ctx)
+		}
+		ctx.Win.PushScope(tag)
+		defer ctx.Win.PopScope()
+		return widget(ctx)
+	}
+}
+
+// FocusScope is like Scope, but Tab and the arrow keys will not move the focus
+// into or out of the scope. Use Window.RequestFocusIn(tag) to move the focus into it.
+func FocusScope(tag any, widget Wid) Wid {
+	return func(ctx Ctx) Dim {
+		if ctx.Mode != RenderChildren {
+			return widget(ctx)
+		}
+		ctx.Win.PushFocusScope(tag)
+		defer ctx.Win.PopFocusScope()
+		return widget(ctx)
+	}
+}
@@ -21,6 +21,10 @@ type ScrollStyle struct {
 	ThumbCornerRadius float32
 	// ScrollFactor is the fraction of the visible area that is scrolled.
 	ScrollFactor float32
+	// ContentWidth is the width of the content in a ScrollerXY.
+	// When zero, the width of the widest child is used.
+	// In a Grid, it is the width the columns are fitted to, when wider than the grid.
+	ContentWidth float32
 }
 
 var DefaultScrollStyle = ScrollStyle{
@@ -55,11 +59,20 @@ type ScrollState struct {
 	Dy float32
 	// Dragging is a flag that is true while the mous button is down in the scrollbar
 	Dragging bool
-	// StartPos is the mouse position on start of dragging
+	// StartPos is the mouse position while dragging.
+	// Deprecated: Use Window.Gesture to follow the mouse.
 	StartPos      float32
 	AtEnd         bool
 	Id            int
 	PendingScroll float32
+	// Xpos is the amount scrolled to the right, from 0 to Xmax-VisibleWidth
+	Xpos float32
+	// Xmax is the total width of the content
+	Xmax float32
+	// DraggingX is true while the mouse button is down in the horizontal scrollbar
+	DraggingX bool
+	// dragYpos is Ypos when the thumb drag started
+	dragYpos float32
 }
 
 func doScrolling(ctx Ctx, state *ScrollState, f func(n int) float32) {
@@ -82,26 +95,8 @@ func doScrolling(ctx Ctx, state *ScrollState, f func(n int) float32) {
 
 // VertScollbarUserInput will draw a bar at the right edge of the area r.
 func VertScollbarUserInput(ctx Ctx, state *ScrollState, style *ScrollStyle) {
-	state.Dragging = state.Dragging && ctx.Win.LeftBtnDown()
 	dy := float32(0.0)
-	if state.Dragging {
-		// Mouse dragging scroller thumb
-		mouseY := ctx.Win.MousePos().Y
-		mouseDelta := mouseY - state.StartPos
-		thumbHeight := min(ctx.Rect.H, max(style.MinThumbHeight, ctx.Rect.H*ctx.Rect.H/state.Ymax))
-		dy = mouseDelta * (state.Ymax - ctx.Rect.H) / (ctx.Rect.H - thumbHeight)
-		if dy != 0 {
-			if mouseY > ctx.Y && mouseY < ctx.Y+ctx.H {
-				state.StartPos = mouseY
-				ctx.Win.Invalidate()
-				scrollDebug("Drag", "mouseDelta", mouseDelta, "dy", dy, "Ypos", int(state.Ypos), "Ymax", int(state.Ymax), "rect.H", int(ctx.Rect.H), "state.StartPos", int(state.StartPos), "NotAtEnd", state.Ypos < state.Ymax-ctx.Rect.H-0.01)
-			} else {
-				scrollDebug("Dragging outside ctx.Rect", "MouseY", mouseY, "dy", dy, "ctx.Y", ctx.Y, "ctx.H", ctx.Rect.H)
-				dy = 0
-			}
-			state.PendingScroll += dy
-		}
-	} else if ctx.Win.Hovered(ctx.Rect) {
+	if ctx.Win.Hovered(ctx.Rect) {
 		scr := ctx.Win.ScrolledY()
 		w := sys.GetCurrentWindow()
 		if w == nil {
@@ -117,33 +112,107 @@ func VertScollbarUserInput(ctx Ctx, state *ScrollState, style *ScrollStyle) {
 			}
 			state.PendingScroll += dy
 			scrollDebug("ScrollWheelInput:", "dy", int(dy))
-		} else if w.LastKey == sys.KeyHome {
+		} else {
+			// The keys are handled after the focus navigation, so an arrow key
+			// that moves the focus does not scroll as well.
+			h := ctx.H
+			ctx.Win.HandleUnusedKeys(func() { scrollKeys(ctx.Win, state, h) })
+		}
+	}
+}
+
+// scrollKeys scrolls by the Home, End, Up, Down, PgUp and PgDn keys not used by other widgets.
+func scrollKeys(win *sys.Window, state *ScrollState, h float32) {
+	for _, e := range win.KeyEvents() {
+		if !e.Typed() {
+			continue
+		}
+		switch e.Key {
+		case sys.KeyHome:
 			scrollDebug("Scroll KeyHome")
 			state.AtEnd = false
-			ctx.Win.Invalidate()
 			state.PendingScroll = -999999
-		} else if w.LastKey == sys.KeyEnd {
+		case sys.KeyEnd:
 			scrollDebug("Scroll KeyEnd")
-			ctx.Win.Invalidate()
 			state.PendingScroll = 999999
-		} else if w.LastKey == sys.KeyDown {
+		case sys.KeyDown:
 			scrollDebug("Scroll KeyDown")
-			state.PendingScroll = ctx.H / 5
-		} else if w.LastKey == sys.KeyUp {
+			state.PendingScroll = h / 5
+		case sys.KeyUp:
 			scrollDebug("Scroll KeyUp")
 			state.AtEnd = false
-			state.PendingScroll -= ctx.H / 5
-		} else if w.LastKey == sys.KeyPageDown {
-			scrollDebug("Scroll KeyDown")
-			state.PendingScroll += ctx.H
-		} else if w.LastKey == sys.KeyPageUp {
-			scrollDebug("Scroll KeyUp")
+			state.PendingScroll -= h / 5
+		case sys.KeyPageDown:
+			scrollDebug("Scroll KeyPageDown")
+			state.PendingScroll += h
+		case sys.KeyPageUp:
+			scrollDebug("Scroll KeyPageUp")
 			state.AtEnd = false
-			state.PendingScroll -= ctx.H
+			state.PendingScroll -= h
+		default:
+			continue
 		}
+		e.Consume()
+		win.LastKey = 0
+		win.Invalidate()
+	}
+}
+
+// HorScrollbarUserInput handles horizontal scrolling by the mouse wheel (with shift).
+// Dragging the thumb is handled by DrawHorScrollbar.
+func HorScrollbarUserInput(ctx Ctx, state *ScrollState, style *ScrollStyle) {
+	dx := float32(0.0)
+	if ctx.Win.Hovered(ctx.Rect) {
+		// Scrolling left gives positive scr value
+		dx = -(ctx.Win.ScrolledX() * ctx.Rect.W) * style.ScrollFactor
+	}
+	if dx != 0 {
+		state.Xpos = max(0, min(state.Xpos+dx, state.Xmax-ctx.Rect.W))
+		scrollDebug("Horizontal scroll", "dx", int(dx), "Xpos", int(state.Xpos), "Xmax", int(state.Xmax))
+		ctx.Win.Invalidate()
 	}
 }
 
+// DrawHorScrollbar will draw a bar at the bottom edge of the area r.
+// state.Xpos is the position. (Xmax-Xvis) is max Xpos. Xvis is the visible part
+func DrawHorScrollbar(ctx Ctx, state *ScrollState, style *ScrollStyle) {
+	if ctx.Rect.W >= state.Xmax {
+		return
+	}
+	if style == nil {
+		style = &DefaultScrollStyle
+	}
+	barRect := f32.Rect{
+		X: ctx.Rect.X + style.ScrollerMargin,
+		Y: ctx.Rect.Y + ctx.Rect.H - style.ScrollbarWidth,
+		W: ctx.Rect.W - 2*style.ScrollerMargin,
+		H: style.ScrollbarWidth}
+	if state.Ymax > ctx.Rect.H {
+		// Leave the corner free for the vertical scrollbar
+		barRect.W -= style.ScrollbarWidth
+	}
+	thumbWidth := min(barRect.W, max(style.MinThumbHeight, ctx.Rect.W*barRect.W/state.Xmax))
+	thumbPos := state.Xpos * (barRect.W - thumbWidth) / (state.Xmax - ctx.Rect.W)
+	thumbRect := f32.Rect{X: barRect.X + thumbPos, Y: barRect.Y + style.ScrollerMargin, W: thumbWidth, H: style.ScrollbarWidth - style.ScrollerMargin*2}
+	// Mouse dragging scroller thumb
+	g := ctx.Win.Gesture(thumbRect, &state.Xpos)
+	state.DraggingX = g.Dragging
+	if g.DragStart {
+		scrollDebug("Scrollbar: Start horizontal dragging at", "X", ctx.Win.MousePos().X)
+	}
+	if g.Delta.X != 0 {
+		dx := g.Delta.X * (state.Xmax - ctx.Rect.W) / (barRect.W - thumbWidth)
+		state.Xpos = max(0, min(state.Xpos+dx, state.Xmax-ctx.Rect.W))
+		scrollDebug("Horizontal drag", "dx", int(dx), "Xpos", int(state.Xpos), "Xmax", int(state.Xmax))
+		ctx.Win.Invalidate()
+	}
+	// Draw scrollbar track
+	ctx.Win.Gd.RoundedRect(barRect, style.ThumbCornerRadius, 0.0, theme.SurfaceContainer.Fg().MultAlpha(style.TrackAlpha), f32.Transparent)
+	// Draw thumb
+	alpha := f32.Sel(g.Hovered || g.Dragging, style.NormalAlpha, style.HoverAlpha)
+	ctx.Win.Gd.RoundedRect(thumbRect, style.ThumbCornerRadius, 0.0, theme.SurfaceContainer.Fg().MultAlpha(alpha), f32.Transparent)
+}
+
 // DrawVertScrollbar will draw a bar at the right edge of the area r.
 // state.Ypos is the position. (Ymax-Yvis) is max Ypos. Yvis is the visible part
 func DrawVertScrollbar(ctx Ctx, state *ScrollState, style *ScrollStyle) {
@@ -168,17 +237,29 @@ func DrawVertScrollbar(ctx Ctx, state *ScrollState, style *ScrollStyle) {
 		thumbPos = barRect.H - thumbHeight
 	}
 	thumbRect := f32.Rect{X: barRect.X + style.ScrollerMargin, Y: barRect.Y + thumbPos, W: style.ScrollbarWidth - style.ScrollerMargin*2, H: thumbHeight}
+	// Mouse dragging scroller thumb
+	g := ctx.Win.Gesture(thumbRect, &state.Ypos)
+	state.Dragging = g.Dragging
+	if g.Dragging {
+		state.StartPos = ctx.Win.MousePos().Y
+	}
+	if g.DragStart {
+		state.dragYpos = state.Ypos
+		scrollDebug("Scrollbar: Start dragging at", "Y", ctx.Win.MousePos().Y)
+	}
+	if g.Dragging && g.Delta.Y != 0 {
+		// The thumb follows the mouse from the drag start, also when it is outside the scroll area.
+		ypos := state.dragYpos + g.Offset.Y*(state.Ymax-ctx.Rect.H)/(barRect.H-thumbHeight)
+		ypos = max(0, min(ypos, state.Ymax-ctx.Rect.H))
+		state.PendingScroll = ypos - state.Ypos
+		ctx.Win.Invalidate()
+		scrollDebug("Drag", "Offset", g.Offset.Y, "ypos", int(ypos), "Ypos", int(state.Ypos), "Ymax", int(state.Ymax), "rect.H", int(ctx.Rect.H))
+	}
 	// Draw scrollbar track
 	ctx.Win.Gd.RoundedRect(barRect, style.ThumbCornerRadius, 0.0, theme.SurfaceContainer.Fg().MultAlpha(style.TrackAlpha), f32.Transparent)
 	// Draw thumb
-	alpha := f32.Sel(ctx.Win.Hovered(thumbRect) || state.Dragging, style.NormalAlpha, style.HoverAlpha)
+	alpha := f32.Sel(g.Hovered || g.Dragging, style.NormalAlpha, style.HoverAlpha)
 	ctx.Win.Gd.RoundedRect(thumbRect, style.ThumbCornerRadius, 0.0, theme.SurfaceContainer.Fg().MultAlpha(alpha), f32.Transparent)
-	// Start dragging if mouse pressed
-	if ctx.Win.LeftBtnPressed(thumbRect) && !state.Dragging {
-		state.Dragging = true
-		state.StartPos = ctx.Win.StartDrag().Y
-		scrollDebug("Scrollbar: Start dragging at", "StartPos", state.StartPos, "win.dragging", ctx.Win.Dragging)
-	}
 }
 
 // scrollUp with negative yScroll
@@ -312,39 +393,79 @@ func Scroller(state *ScrollState, style *ScrollStyle, widgets ...Wid) Wid {
 		return nil
 	}
 	return func(ctx Ctx) Dim {
-		ctx0 := ctx
 		if ctx.Mode != RenderChildren {
 			return Dim{W: style.Width, H: style.Height, Baseline: 0}
 		}
-		ctx0.Rect.Y -= state.Dy
-		sumH := -state.Dy
-		ctx0.Rect.H += state.Dy
-		ctx.Win.Gd.Clip(ctx.Rect)
-		for i := state.Npos; i < len(widgets) && sumH < ctx.Rect.H*2 && ctx0.H > 0; i++ {
-			dim := widgets[i](ctx0)
-			ctx0.Rect.Y += dim.H
-			ctx0.Rect.H -= dim.H
-			sumH += dim.H
-			updateYmax(i, state, dim.H)
+		scrollChildren(ctx, ctx.X, ctx.W, state, style, widgets)
+		return Dim{ctx.W, ctx.H, 0}
+	}
+}
+
+// ScrollerXY is a scrollable container that scrolls both vertically and horizontally.
+// The children are given the width style.ContentWidth, or the visible width if that is larger.
+func ScrollerXY(state *ScrollState, style *ScrollStyle, widgets ...Wid) Wid {
+	f32.ExitIf(state == nil, "Scroller state must not be nil")
+	if style == nil {
+		style = &DefaultScrollStyle
+	}
+	return func(ctx Ctx) Dim {
+		if ctx.Mode != RenderChildren {
+			return Dim{W: style.Width, H: style.Height, Baseline: 0}
 		}
-		gpu.NoClip()
-		if state.Nmax < len(widgets) {
-			// If we do not have correct Ymax/Nmax, we need to calculate them.
-			for i := max(0, state.Nmax-1); i < len(widgets); i++ {
-				ctx0.Mode = CollectHeights
-				dim := widgets[i](ctx0)
-				state.Ymax += dim.H
-				state.Nmax = i + 1
-			}
-			state.Nmax = len(widgets)
-			slog.Debug("Calculate", "Ymax", state.Ymax, "Nmax", state.Nmax)
+		contentW := max(ctx.W, style.ContentWidth)
+		ctx0 := ctx
+		if state.Xmax > ctx.W {
+			// Make room for the horizontal scrollbar below the content
+			ctx0.H -= style.ScrollbarWidth
 		}
-		VertScollbarUserInput(ctx, state, style)
-		ctx0.Mode = CollectHeights
-		doScrolling(ctx, state, func(n int) float32 {
-			return widgets[n](ctx0).H
-		})
-		DrawVertScrollbar(ctx, state, style)
+		state.Xpos = max(0, min(state.Xpos, state.Xmax-ctx.W))
+		HorScrollbarUserInput(ctx, state, style)
+		maxW := scrollChildren(ctx0, ctx.X-state.Xpos, contentW, state, style, widgets)
+		state.Xmax = max(contentW, maxW)
+		DrawHorScrollbar(ctx, state, style)
 		return Dim{ctx.W, ctx.H, 0}
 	}
 }
+
+// scrollChildren draws the visible children, handles vertical scrolling and draws the vertical scrollbar.
+// The children are drawn at x with width w. It returns the width of the widest child drawn.
+func scrollChildren(ctx Ctx, x, w float32, state *ScrollState, style *ScrollStyle, widgets []Wid) float32 {
+	ctx0 := ctx
+	ctx0.Rect.X = x
+	ctx0.Rect.W = w
+	ctx0.Rect.Y -= state.Dy
+	sumH := -state.Dy
+	maxW := float32(0)
+	ctx0.Rect.H += state.Dy
+	ctx.Win.Gd.Clip(ctx.Rect)
+	ctx.Win.PushNavGroup(sys.NavVertical, 0)
+	for i := state.Npos; i < len(widgets) && sumH < ctx.Rect.H*2 && ctx0.H > 0; i++ {
+		ctx.Win.NavChild(i)
+		dim := widgets[i](ctx0)
+		ctx0.Rect.Y += dim.H
+		ctx0.Rect.H -= dim.H
+		sumH += dim.H
+		maxW = max(maxW, dim.W)
+		updateYmax(i, state, dim.H)
+	}
+	ctx.Win.PopNavGroup()
+	gpu.NoClip()
+	if state.Nmax < len(widgets) {
+		// If we do not have correct Ymax/Nmax, we need to calculate them.
+		for i := max(0, state.Nmax-1); i < len(widgets); i++ {
+			ctx0.Mode = CollectHeights
+			dim := widgets[i](ctx0)
+			state.Ymax += dim.H
+			state.Nmax = i + 1
+		}
+		state.Nmax = len(widgets)
+		slog.Debug("Calculate", "Ymax", state.Ymax, "Nmax", state.Nmax)
+	}
+	VertScollbarUserInput(ctx, state, style)
+	ctx0.Mode = CollectHeights
+	doScrolling(ctx, state, func(n int) float32 {
+		return widgets[n](ctx0).H
+	})
+	DrawVertScrollbar(ctx, state, style)
+	return maxW
+}
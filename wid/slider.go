@@ -0,0 +1,354 @@
+package wid
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/jkvatne/jkvgui/f32"
+	"github.com/jkvatne/jkvgui/gpu"
+	"github.com/jkvatne/jkvgui/gpu/font"
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/theme"
+)
+
+// Number is the value types accepted by the numeric widgets
+type Number interface {
+	~int | ~float32 | ~float64
+}
+
+// SliderStyle is the style of Slider and RangeSlider
+type SliderStyle struct {
+	FontNo   int
+	Vertical bool
+	// Length is the width of a horizontal slider, or the height of a vertical slider.
+	// A value of 0 uses the available space, and values below 1.0 are fractions of it.
+	Length     float32
+	TrackWidth float32
+	ThumbSize  float32
+	TrackRole  theme.UIRole
+	// ActiveRole is used for the thumbs and for the track between the start and the value
+	ActiveRole theme.UIRole
+	// Ticks is the number of intervals between the tick marks, or 0 for no tick marks
+	Ticks      int
+	TickLength float32
+	// Labels draws the values at the tick marks, or at the ends when there are no tick marks
+	Labels bool
+	// ShowValue draws the value above the thumb, or to the left of it for vertical sliders
+	ShowValue bool
+	// Format is the fmt format of the values shown, like "%.1f°C".
+	// When empty, the number of decimals in the step is used.
+	Format string
+	// PageSteps is the number of steps moved by PageUp and PageDown
+	PageSteps  int
+	Padding    f32.Padding
+	Spacing    float32
+	ShadowSize float32
+}
+
+var DefaultSlider = &SliderStyle{
+	FontNo:     gpu.Normal10,
+	TrackWidth: 4,
+	ThumbSize:  16,
+	TrackRole:  theme.SurfaceContainer,
+	ActiveRole: theme.Primary,
+	TickLength: 4,
+	PageSteps:  10,
+	Padding:    f32.Padding{L: 4, T: 2, R: 4, B: 2},
+	Spacing:    2,
+	ShadowSize: 4,
+}
+
+// Vert returns a copy of the style for a vertical slider with the given height
+func (s *SliderStyle) Vert(length float32) *SliderStyle {
+	ss := *s
+	ss.Vertical = true
+	ss.Length = length
+	return &ss
+}
+
+// numberRange is the limits and step of a numeric widget, converted to float64
+type numberRange struct {
+	min, max, step float64
+	integer        bool
+}
+
+func makeRange[T Number](minV, maxV, step T) numberRange {
+	h := 0.5
+	return numberRange{min: float64(minV), max: float64(max(minV, maxV)), step: float64(step), integer: float64(T(h)) != h}
+}
+
+// decimals returns the number of decimals needed to show values with the given step
+func (r numberRange) decimals() int {
+	if r.integer {
+		return 0
+	}
+	if r.step <= 0 {
+		return 2
+	}
+	s := strconv.FormatFloat(r.step, 'f', -1, 64)
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return len(s) - i - 1
+	}
+	return 0
+}
+
+// snap limits the value to the range, and rounds it to the nearest step
+func (r numberRange) snap(x float64) float64 {
+	x = max(r.min, min(x, r.max))
+	if r.step > 0 {
+		x = min(r.min+math.Round((x-r.min)/r.step)*r.step, r.max)
+		p := math.Pow10(r.decimals())
+		x = math.Round(x*p) / p
+	}
+	if r.integer {
+		x = math.Round(x)
+	}
+	return x
+}
+
+// text formats the value, using the format if it is not empty
+func (r numberRange) text(x float64, format string) string {
+	if format != "" {
+		return fmt.Sprintf(format, x)
+	}
+	return strconv.FormatFloat(x, 'f', r.decimals(), 64)
+}
+
+// keyStep returns the change in value for the arrow keys
+func (r numberRange) keyStep() float64 {
+	if r.step > 0 {
+		return r.step
+	}
+	if r.integer {
+		return 1
+	}
+	return (r.max - r.min) / 100
+}
+
+// Slider sets the value by dragging the thumb along the track, or by clicking on the track.
+// When focused, the arrow keys change the value one step, PageUp and PageDown by
+// style.PageSteps steps, and Home and End go to the limits.
+// A step of 0 gives a continuous value. The hint is also used as the name for screen readers.
+func Slider[T Number](value *T, minV, maxV, step T, style *SliderStyle, hint string) Wid {
+	f32.ExitIf(value == nil, "Slider value must not be nil")
+	return slider(makeRange(minV, maxV, step), []any{value}, style, hint,
+		func(i int) float64 { return float64(*value) },
+		func(i int, x float64) { *value = T(x) })
+}
+
+// RangeSlider is a slider with two thumbs, setting the low and high values of a range.
+// The low value can not be above the high value. Each thumb can get focus, and is moved by
+// the keys like the Slider.
+func RangeSlider[T Number](low, high *T, minV, maxV, step T, style *SliderStyle, hint string) Wid {
+	f32.ExitIf(low == nil || high == nil, "RangeSlider values must not be nil")
+	values := []*T{low, high}
+	return slider(makeRange(minV, maxV, step), []any{low, high}, style, hint,
+		func(i int) float64 { return float64(*values[i]) },
+		func(i int, x float64) { *values[i] = T(x) })
+}
+
+// slider is the implementation of Slider and RangeSlider. The tags are the value pointers,
+// used for focus and dragging, and get and set converts the values to and from float64.
+func slider(r numberRange, tags []any, style *SliderStyle, hint string, get func(i int) float64, set func(i int, x float64)) Wid {
+	if style == nil {
+		style = DefaultSlider
+	}
+	f := font.Get(style.FontNo)
+	pad := style.Padding
+	thumb := style.ThumbSize
+	textW := max(f.Width(r.text(r.min, style.Format)), f.Width(r.text(r.max, style.Format)))
+	tickW := f32.Sel(style.Ticks > 0, 0, style.TickLength+style.Spacing)
+	var cross float32
+	if style.Vertical {
+		cross = pad.L + f32.Sel(style.ShowValue, 0, textW+style.Spacing) + thumb + tickW + f32.Sel(style.Labels, 0, textW) + pad.R
+	} else {
+		cross = pad.T + f32.Sel(style.ShowValue, 0, f.Height+style.Spacing) + thumb + tickW + f32.Sel(style.Labels, 0, f.Height) + pad.B
+	}
+
+	return func(ctx Ctx) Dim {
+		baseline := pad.T + f32.Sel(style.ShowValue, 0, f.Height+style.Spacing) + thumb/2 - f.Height/2 + f.Baseline
+		if ctx.Mode != RenderChildren {
+			if style.Vertical {
+				return Dim{W: cross, H: style.Length}
+			}
+			return Dim{W: style.Length, H: cross, Baseline: baseline}
+		}
+		rect := ctx.Rect.Inset(pad, 0)
+		// The track goes from a (the minimum) to b (the maximum)
+		var a, b f32.Pos
+		if style.Vertical {
+			margin := max(thumb/2, f32.Sel(style.Labels || style.ShowValue, 0, f.Height/2))
+			x := rect.X + f32.Sel(style.ShowValue, 0, textW+style.Spacing) + thumb/2
+			a, b = f32.Pos{X: x, Y: rect.Y + rect.H - margin}, f32.Pos{X: x, Y: rect.Y + margin}
+		} else {
+			margin := max(thumb/2, f32.Sel(style.Labels || style.ShowValue, 0, textW/2))
+			y := rect.Y + f32.Sel(style.ShowValue, 0, f.Height+style.Spacing) + thumb/2
+			a, b = f32.Pos{X: rect.X + margin, Y: y}, f32.Pos{X: rect.X + rect.W - margin, Y: y}
+		}
+		d := b.Sub(a)
+		point := func(x float64) f32.Pos {
+			t := float32(0)
+			if r.max > r.min {
+				t = float32((x - r.min) / (r.max - r.min))
+			}
+			return f32.Pos{X: a.X + d.X*t, Y: a.Y + d.Y*t}
+		}
+		valueAt := func(p f32.Pos) float64 {
+			t := ((p.X-a.X)*d.X + (p.Y-a.Y)*d.Y) / max(d.X*d.X+d.Y*d.Y, 1)
+			return r.snap(r.min + float64(max(0, min(t, 1)))*(r.max-r.min))
+		}
+		// segment returns the part of the track between p and q
+		segment := func(p, q f32.Pos) f32.Rect {
+			s := f32.Rect{X: min(p.X, q.X), Y: min(p.Y, q.Y), W: f32.Abs(p.X - q.X), H: f32.Abs(p.Y - q.Y)}
+			if style.Vertical {
+				s.X -= style.TrackWidth / 2
+				s.W = style.TrackWidth
+			} else {
+				s.Y -= style.TrackWidth / 2
+				s.H = style.TrackWidth
+			}
+			return s
+		}
+
+		// Mouse input. When the track is pressed, the nearest thumb is moved to the mouse and dragged.
+		mouse := ctx.Win.MousePos()
+		n := len(tags)
+		order := []int{0}
+		if n == 2 {
+			order = []int{0, 1}
+			p0, p1 := point(get(0)), point(get(1))
+			d0, d1 := mouse.Sub(p0), mouse.Sub(p1)
+			dist0, dist1 := d0.X*d0.X+d0.Y*d0.Y, d1.X*d1.X+d1.Y*d1.Y
+			if dist1 < dist0 || dist1 == dist0 && (mouse.X-p1.X)*d.X+(mouse.Y-p1.Y)*d.Y > 0 {
+				order = []int{1, 0}
+			}
+		}
+		active := make([]bool, n)
+		for k, i := range order {
+			g := ctx.Win.Gesture(ctx.Rect, tags[i])
+			if g.DragStart {
+				ctx.Win.SetFocusedTag(tags[i])
+			}
+			if g.Dragging {
+				x := valueAt(mouse)
+				if n == 2 && i == 0 {
+					x = min(x, get(1))
+				} else if n == 2 {
+					x = max(x, get(0))
+				}
+				if x != get(i) {
+					set(i, x)
+					ctx.Win.Invalidate()
+				}
+			}
+			active[i] = g.Dragging || g.Hovered && k == 0
+			if g.Hovered && k == 0 {
+				Hint(ctx, hint, tags[i])
+			}
+		}
+
+		// Keyboard input
+		focused := make([]bool, n)
+		for i := range tags {
+			focused[i] = ctx.Win.At(tags[i])
+			if !focused[i] {
+				continue
+			}
+			lo, hi := r.min, r.max
+			if n == 2 && i == 0 {
+				hi = get(1)
+			} else if n == 2 {
+				lo = get(0)
+			}
+			x := get(i)
+			step := r.keyStep()
+			switch {
+			case ctx.Win.TakeKey(sys.KeyRight, sys.KeyUp):
+				x += step
+			case ctx.Win.TakeKey(sys.KeyLeft, sys.KeyDown):
+				x -= step
+			case ctx.Win.TakeKey(sys.KeyPageUp):
+				x += step * float64(max(1, style.PageSteps))
+			case ctx.Win.TakeKey(sys.KeyPageDown):
+				x -= step * float64(max(1, style.PageSteps))
+			case ctx.Win.TakeKey(sys.KeyHome):
+				x = lo
+			case ctx.Win.TakeKey(sys.KeyEnd):
+				x = hi
+			default:
+				continue
+			}
+			x = max(lo, min(r.snap(x), hi))
+			if x != get(i) {
+				set(i, x)
+				ctx.Win.Invalidate()
+			}
+		}
+
+		// Draw track, tick marks and labels
+		ctx.Win.Gd.RoundedRect(segment(a, b), style.TrackWidth/2, 0, style.TrackRole.Bg(), style.TrackRole.Bg())
+		start := a
+		if n == 2 {
+			start = point(get(0))
+		}
+		ctx.Win.Gd.RoundedRect(segment(start, point(get(n-1))), style.TrackWidth/2, 0, style.ActiveRole.Bg(), style.ActiveRole.Bg())
+		fg := theme.OnSurface.Fg()
+		intervals := style.Ticks
+		if intervals <= 0 && style.Labels {
+			intervals = 1
+		}
+		for i := 0; i <= intervals; i++ {
+			x := r.min + (r.max-r.min)*float64(i)/float64(max(1, intervals))
+			p := point(x)
+			text := r.text(x, style.Format)
+			if style.Vertical {
+				x0 := p.X + thumb/2 + style.Spacing
+				if style.Ticks > 0 {
+					ctx.Win.Gd.HorLine(x0, x0+style.TickLength, p.Y, 1, fg.MultAlpha(0.6))
+				}
+				if style.Labels {
+					f.DrawText(ctx.Win.Gd, x0+tickW, p.Y-f.Height/2+f.Baseline, fg, 0, gpu.LTR, text)
+				}
+			} else {
+				y0 := p.Y + thumb/2 + style.Spacing
+				if style.Ticks > 0 {
+					ctx.Win.Gd.VertLine(p.X, y0, y0+style.TickLength, 1, fg.MultAlpha(0.6))
+				}
+				if style.Labels {
+					f.DrawText(ctx.Win.Gd, p.X-f.Width(text)/2, y0+tickW+f.Baseline, fg, 0, gpu.LTR, text)
+				}
+			}
+		}
+
+		// Draw the thumbs and the values
+		for i := range tags {
+			x := get(i)
+			p := point(x)
+			thumbRect := f32.Rect{X: p.X - thumb/2, Y: p.Y - thumb/2, W: thumb, H: thumb}
+			if active[i] || focused[i] {
+				ctx.Win.Gd.Shade(thumbRect.Increase(style.ShadowSize), -1, f32.Shade, style.ShadowSize)
+			}
+			ctx.Win.Gd.RoundedRect(thumbRect, -1, 0, style.ActiveRole.Bg(), style.ActiveRole.Bg())
+			text := r.text(x, style.Format)
+			if style.ShowValue && style.Vertical {
+				f.DrawText(ctx.Win.Gd, p.X-thumb/2-style.Spacing-f.Width(text), p.Y-f.Height/2+f.Baseline, fg, 0, gpu.LTR, text)
+			} else if style.ShowValue {
+				f.DrawText(ctx.Win.Gd, p.X-f.Width(text)/2, rect.Y+f.Baseline, fg, 0, gpu.LTR, text)
+			}
+			bounds := ctx.Rect
+			if n == 2 {
+				bounds = thumbRect
+			}
+			ctx.Win.AddAccessNode(sys.AccessNode{Role: sys.RoleSlider, Name: hint, Value: text, State: accessState(false),
+				Bounds: bounds, Tag: tags[i]})
+		}
+		DrawDebuggingInfo(ctx, rect, rect, ctx.Rect)
+
+		if style.Vertical {
+			return Dim{W: cross, H: ctx.H}
+		}
+		return Dim{W: ctx.W, H: cross, Baseline: baseline}
+	}
+}
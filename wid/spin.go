@@ -0,0 +1,225 @@
+package wid
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/jkvatne/jkvgui/f32"
+	"github.com/jkvatne/jkvgui/gpu"
+	"github.com/jkvatne/jkvgui/gpu/font"
+	"github.com/jkvatne/jkvgui/sys"
+)
+
+// SpinStyle is the style of a Spin box
+type SpinStyle struct {
+	EditStyle
+	// PageSteps is the number of steps for PageUp and PageDown
+	PageSteps int
+}
+
+var DefaultSpin = &SpinStyle{
+	EditStyle: DefaultEdit,
+	PageSteps: 10,
+}
+
+// Size returns a copy of the style with the given label and edit sizes
+func (s *SpinStyle) Size(wl, we float32) *SpinStyle {
+	ss := *s
+	ss.EditSize = we
+	ss.LabelSize = wl
+	return &ss
+}
+
+// format returns the value as text, with the number of decimals given by the step.
+// If the step is 0, dp decimals are used.
+func (r numberRange) format(x float64, dp int) string {
+	if r.step > 0 || r.integer {
+		dp = r.decimals()
+	}
+	return strconv.FormatFloat(x, 'f', dp, 64)
+}
+
+// parse converts the text to a value, and checks that it is inside the range.
+// A unit typed after the number is ignored.
+func (r numberRange) parse(s string, unit string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if unit != "" {
+		s = strings.TrimSpace(strings.TrimSuffix(s, unit))
+	}
+	x, err := strconv.ParseFloat(s, 64)
+	if err != nil || r.integer && x != math.Trunc(x) {
+		return 0, fmt.Errorf("%q is not a valid number", s)
+	}
+	if x < r.min || x > r.max {
+		return x, fmt.Errorf("the value must be from %s to %s",
+			strconv.FormatFloat(r.min, 'f', -1, 64), strconv.FormatFloat(r.max, 'f', -1, 64))
+	}
+	return x, nil
+}
+
+// Spin is an editor for numbers with limits, a step and a unit like "°C" or "kHz" shown after the value.
+// The value is changed by the step buttons, the mouse wheel, and the Up and Down keys, or by
+// PageUp and PageDown for style.PageSteps steps. A typed value is used when Enter is pressed or
+// the focus is lost. Values that can not be parsed or are out of range are shown with the error colors,
+// and are not used. Escape reverts the text to the current value.
+// The action is called after the value is changed by the user, and can be nil.
+func Spin[T Number](value *T, minV, maxV, step T, label string, unit string, action func(), style *SpinStyle) Wid {
+	f32.ExitIf(value == nil, "Spin value must not be nil")
+	if style == nil {
+		style = DefaultSpin
+	}
+	r := makeRange(minV, maxV, step)
+	text := func() string { return r.format(float64(*value), style.Dp) }
+
+	StateMapMutex.RLock()
+	state := StateMap[value]
+	StateMapMutex.RUnlock()
+	if state == nil {
+		StateMapMutex.Lock()
+		state = &EditState{value: value, dp: style.Dp}
+		state.Buffer.Init(text())
+		StateMap[value] = state
+		StateMapMutex.Unlock()
+	}
+
+	f := font.Get(style.FontNo)
+	unitText := ""
+	if unit != "" {
+		unitText = " " + unit
+	}
+
+	// setValue sets the value and the text, and calls the action if the value changed
+	setValue := func(ctx Ctx, x float64) {
+		old := *value
+		*value = T(x)
+		state.Buffer.Init(text())
+		state.SelStart = state.Buffer.RuneCount()
+		state.SelEnd = state.SelStart
+		state.modified = false
+		ctx.Win.Invalidate()
+		if *value != old && action != nil {
+			action()
+		}
+	}
+	// stepValue adds n steps to the typed value, or to the current value if the text is not valid
+	stepValue := func(ctx Ctx, n int) {
+		x, err := r.parse(state.Buffer.String(), unit)
+		if err != nil {
+			x = float64(*value)
+		}
+		setValue(ctx, r.snap(x+float64(n)*r.keyStep()))
+	}
+	// commit uses the typed value if it is valid, and reverts the text if revert is true
+	commit := func(ctx Ctx, revert bool) {
+		x, err := r.parse(state.Buffer.String(), unit)
+		if err == nil {
+			setValue(ctx, x)
+		} else if revert {
+			slog.Debug("Spin: invalid value reverted", "error", err)
+			setValue(ctx, float64(*value))
+		}
+	}
+
+	return func(ctx Ctx) Dim {
+		dim, frameRect, valueRect, labelRect := CalculateRects(label != "", &style.EditStyle, ctx.Rect)
+		if ctx.Mode != RenderChildren {
+			return Dim{W: dim.W, H: dim.H, Baseline: f.Baseline + style.Top()}
+		}
+		bw := style.BorderWidth
+		// The step buttons are at the right end of the frame
+		btnW := f.Height
+		valueRect.W -= btnW
+		upRect := f32.Rect{X: valueRect.X + valueRect.W, Y: frameRect.Y + bw, W: btnW, H: frameRect.H/2 - bw}
+		downRect := upRect
+		downRect.Y += upRect.H
+
+		focused := ctx.Win.At(value)
+		if !state.modified && !focused && state.Buffer.String() != text() {
+			// The value has been changed by the application
+			state.Buffer.Init(text())
+		}
+		if !style.Disabled() {
+			if ctx.Win.Focused {
+				EditMouseHandler(ctx, state, valueRect, f, value)
+			}
+			if ctx.Win.LeftBtnClick(upRect) {
+				ctx.Win.SetFocusedTag(value)
+				stepValue(ctx, 1)
+			} else if ctx.Win.LeftBtnClick(downRect) {
+				ctx.Win.SetFocusedTag(value)
+				stepValue(ctx, -1)
+			}
+			if ctx.Win.Hovered(frameRect) {
+				if dy := ctx.Win.ScrolledY(); dy != 0 {
+					stepValue(ctx, int(f32.Sel(dy < 0, 1, -1)))
+				}
+			}
+			if focused {
+				bw = min(style.BorderWidth*1.5, style.BorderWidth+1)
+				switch {
+				case ctx.Win.TakeKey(sys.KeyUp):
+					stepValue(ctx, 1)
+				case ctx.Win.TakeKey(sys.KeyDown):
+					stepValue(ctx, -1)
+				case ctx.Win.TakeKey(sys.KeyPageUp):
+					stepValue(ctx, max(1, style.PageSteps))
+				case ctx.Win.TakeKey(sys.KeyPageDown):
+					stepValue(ctx, -max(1, style.PageSteps))
+				case ctx.Win.TakeKey(sys.KeyEnter, sys.KeyKPEnter):
+					commit(ctx, false)
+				case ctx.Win.TakeKey(sys.KeyEscape):
+					setValue(ctx, float64(*value))
+				}
+				EditText(ctx, state, nil)
+			} else if state.modified {
+				// On loss of focus, use the typed value, or revert it if it is not valid
+				commit(ctx, true)
+			}
+		}
+		cnt := state.Buffer.RuneCount()
+		state.SelEnd = min(state.SelEnd, cnt)
+		state.SelStart = min(state.SelStart, cnt)
+
+		_, err := r.parse(state.Buffer.String(), unit)
+		fg, bg, border := editColors(&style.EditStyle, state, err)
+		if err != nil && state.hovered {
+			Hint(ctx, err.Error(), value)
+		}
+		ctx.Win.Gd.RoundedRect(frameRect, style.BorderCornerRadius, bw, bg, border)
+		drawEditText(ctx, &style.EditStyle, state, focused, label, labelRect, valueRect, fg)
+
+		// Draw unit after the value
+		s := state.Buffer.String()
+		if unitText != "" {
+			x := valueRect.X + f.Width(s)
+			f.DrawText(ctx.Win.Gd, x, valueRect.Y+f.Baseline, fg.MultAlpha(0.7), max(0, valueRect.X+valueRect.W-x), gpu.LTR, unitText)
+		}
+
+		// Draw step buttons
+		if !style.Disabled() {
+			for _, b := range []f32.Rect{upRect, downRect} {
+				if ctx.Win.Hovered(b) {
+					ctx.Win.Gd.SolidRect(b, fg.MultAlpha(0.08))
+				}
+			}
+		}
+		ctx.Win.Gd.DrawIcon(upRect.X, upRect.Y+(upRect.H-btnW)/2, btnW, gpu.NavigationArrowDropUp, fg)
+		ctx.Win.Gd.DrawIcon(downRect.X, downRect.Y+(downRect.H-btnW)/2, btnW, gpu.NavigationArrowDropDown, fg)
+
+		DrawDebuggingInfo(ctx, labelRect, valueRect, ctx.Rect)
+
+		as := accessState(style.Disabled())
+		if !style.Disabled() {
+			as |= sys.StateEditable
+		}
+		if err != nil {
+			as |= sys.StateInvalid
+		}
+		ctx.Win.AddAccessNode(sys.AccessNode{Role: sys.RoleSpinButton, Name: label, Value: s + unitText,
+			State: as, Bounds: frameRect, Tag: value})
+		return Dim{W: dim.W, H: dim.H, Baseline: f.Baseline + style.Top()}
+	}
+}
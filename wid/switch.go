@@ -4,6 +4,7 @@ import (
 	"github.com/jkvatne/jkvgui/f32"
 	"github.com/jkvatne/jkvgui/gpu"
 	"github.com/jkvatne/jkvgui/gpu/font"
+	"github.com/jkvatne/jkvgui/sys"
 	"github.com/jkvatne/jkvgui/theme"
 )
 
@@ -80,6 +81,8 @@ func Switch(label string, state *bool, action func(), style *SwitchStyle, hint s
 			ctx.Win.Gd.RoundedRect(knob, -1, 0.0, style.On.Fg(), style.On.Fg())
 		}
 		f.DrawText(ctx.Win.Gd, track.X+width, knob.Y+knob.H+style.Padding.T, style.Track.Fg(), 0, gpu.LTR, label)
+		ctx.Win.AddAccessNode(sys.AccessNode{Role: sys.RoleSwitch, Name: accessName(label, hint),
+			State: accessState(false) | checked(*state), Bounds: ctx.Rect, Tag: state})
 
 		return Dim{W: width, H: height, Baseline: 0}
 	}
@@ -0,0 +1,247 @@
+package wid
+
+import (
+	"github.com/jkvatne/jkvgui/f32"
+	"github.com/jkvatne/jkvgui/gpu"
+	"github.com/jkvatne/jkvgui/gpu/font"
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/theme"
+)
+
+// Tab is one page in a Tabs container.
+type Tab struct {
+	// Title is shown in the tab, and must be unique, as it is used to keep the scroll position of the page.
+	Title string
+	Icon  *gpu.Icon
+	// Closable adds a close button to the tab. Clicking it calls TabsState.OnClose.
+	Closable bool
+	Content  Wid
+}
+
+// TabsState is the state of a Tabs container, and must be kept between frames.
+type TabsState struct {
+	// Active is the index of the tab shown
+	Active int
+	// OnClose is called when the close button of a tab is clicked. It should remove the tab.
+	OnClose        func(i int)
+	scroll         map[string]*ScrollState
+	stripX         float32
+	scrollToActive bool
+}
+
+// TabsStyle is the style of a Tabs container
+type TabsStyle struct {
+	FontNo       int
+	ActiveRole   theme.UIRole
+	InactiveRole theme.UIRole
+	BorderRole   theme.UIRole
+	BorderWidth  float32
+	CornerRadius float32
+	TabPadding   f32.Padding
+	// IconSize is the size of icons and close buttons, relative to the font height
+	IconSize float32
+	Spacing  float32
+	Scroll   ScrollStyle
+}
+
+var DefaultTabs = &TabsStyle{
+	FontNo:       gpu.Normal12,
+	ActiveRole:   theme.PrimaryContainer,
+	InactiveRole: theme.SurfaceContainer,
+	BorderRole:   theme.Outline,
+	BorderWidth:  1,
+	CornerRadius: 4,
+	TabPadding:   f32.Padding{L: 8, T: 4, R: 8, B: 4},
+	IconSize:     1.0,
+	Spacing:      4,
+	Scroll:       DefaultScrollStyle,
+}
+
+// scrollState returns the scroll state for the page with the given title
+func (state *TabsState) scrollState(title string) *ScrollState {
+	if state.scroll == nil {
+		state.scroll = make(map[string]*ScrollState)
+	}
+	s := state.scroll[title]
+	if s == nil {
+		s = &ScrollState{}
+		state.scroll[title] = s
+	}
+	return s
+}
+
+// activate shows the tab, and moves the focus to the tab strip
+func (state *TabsState) activate(win *sys.Window, i int) {
+	state.Active = i
+	state.scrollToActive = true
+	win.SetFocusedTag(state)
+	win.Invalidate()
+}
+
+// Tabs shows the content of the active tab, below a strip with the tab titles.
+// The strip scrolls when the tabs are wider than the container. Ctrl+Tab and Ctrl+Shift+Tab
+// switch tabs when the focus is inside the container, and Left/Right when the strip has focus.
+// Each page is shown in a Scroller, and keeps its scroll position when other tabs are shown.
+func Tabs(state *TabsState, tabs []Tab, style *TabsStyle) Wid {
+	f32.ExitIf(state == nil, "Tabs state must not be nil")
+	if style == nil {
+		style = DefaultTabs
+	}
+	f := font.Get(style.FontNo)
+	pad := style.TabPadding
+	iconSize := f.Height * style.IconSize
+	tabHeight := max(f.Height, iconSize) + pad.T + pad.B
+	widths := make([]float32, len(tabs))
+	total := float32(0)
+	for i, t := range tabs {
+		widths[i] = pad.L + f.Width(t.Title) + pad.R
+		if t.Icon != nil {
+			widths[i] += iconSize + style.Spacing
+		}
+		if t.Closable {
+			widths[i] += iconSize + style.Spacing
+		}
+		total += widths[i]
+	}
+
+	return func(ctx Ctx) Dim {
+		if ctx.Mode != RenderChildren {
+			return Dim{W: ctx.W, H: ctx.H}
+		}
+		state.Active = max(0, min(state.Active, len(tabs)-1))
+		ctx.Win.PushScope(state)
+		defer ctx.Win.PopScope()
+		focused := ctx.Win.At(state)
+
+		stripRect := f32.Rect{X: ctx.X, Y: ctx.Y, W: ctx.W, H: tabHeight}
+		overflow := total > ctx.W
+		if overflow {
+			stripRect.W -= 2 * tabHeight
+			if ctx.Win.Hovered(stripRect) {
+				if dy := ctx.Win.ScrolledY(); dy != 0 {
+					ctx.Win.ScrolledDistY = 0
+					state.stripX -= dy * tabHeight
+					ctx.Win.Invalidate()
+				}
+			}
+		}
+		if state.scrollToActive && len(tabs) > 0 {
+			state.scrollToActive = false
+			x := f32.Sum(widths[:state.Active]...)
+			if x < state.stripX {
+				state.stripX = x
+			} else if x+widths[state.Active] > state.stripX+stripRect.W {
+				state.stripX = x + widths[state.Active] - stripRect.W
+			}
+		}
+		state.stripX = max(0, min(state.stripX, total-stripRect.W))
+
+		pageRect := f32.Rect{X: ctx.X, Y: ctx.Y + tabHeight, W: ctx.W, H: ctx.H - tabHeight}
+		ctx.Win.Gd.RoundedRect(pageRect, 0, style.BorderWidth, f32.Transparent, style.BorderRole.Bg())
+
+		// Draw the tab strip
+		clicked, closed := -1, -1
+		ctx.Win.Gd.Clip(stripRect)
+		x := ctx.X - state.stripX
+		for i, t := range tabs {
+			r := f32.Rect{X: x, Y: ctx.Y, W: widths[i], H: tabHeight}
+			x += widths[i]
+			closeRect := f32.Rect{X: r.X + r.W - pad.R - iconSize, Y: r.Y + pad.T, W: iconSize, H: iconSize}
+			visible := r.X+r.W > stripRect.X && r.X < stripRect.X+stripRect.W
+			if visible && t.Closable && ctx.Win.LeftBtnClick(closeRect) {
+				closed = i
+			} else if visible && ctx.Win.LeftBtnClick(r) {
+				clicked = i
+			}
+			role := style.InactiveRole
+			if i == state.Active {
+				role = style.ActiveRole
+			}
+			bw := style.BorderWidth
+			if focused && i == state.Active {
+				bw++
+			}
+			ctx.Win.Gd.RoundedRect(r, style.CornerRadius, bw, role.Bg(), style.BorderRole.Bg())
+			fg := role.Fg()
+			tx := r.X + pad.L
+			if t.Icon != nil {
+				ctx.Win.Gd.DrawIcon(tx, r.Y+pad.T, iconSize, t.Icon, fg)
+				tx += iconSize + style.Spacing
+			}
+			f.DrawText(ctx.Win.Gd, tx, r.Y+pad.T+f.Baseline, fg, 0, gpu.LTR, t.Title)
+			if t.Closable {
+				if ctx.Win.Hovered(closeRect) {
+					ctx.Win.Gd.RoundedRect(closeRect, iconSize/2, 0, fg.MultAlpha(0.15), f32.Transparent)
+				}
+				ctx.Win.Gd.DrawIcon(closeRect.X, closeRect.Y, iconSize, gpu.NavigationClose, fg)
+			}
+			as := sys.StateFocusable
+			var tag any
+			if i == state.Active {
+				as |= sys.StateSelected
+				tag = state
+			}
+			ctx.Win.AddAccessNode(sys.AccessNode{Role: sys.RoleTab, Name: t.Title, State: as, Bounds: r, Tag: tag})
+		}
+		gpu.NoClip()
+
+		// Arrows for scrolling the strip when the tabs do not fit
+		if overflow {
+			fg := style.InactiveRole.Fg()
+			left := f32.Rect{X: stripRect.X + stripRect.W, Y: ctx.Y, W: tabHeight, H: tabHeight}
+			right := left.Move(tabHeight, 0)
+			ctx.Win.Gd.DrawIcon(left.X, left.Y, tabHeight, gpu.NavigationChevronLeft, fg)
+			ctx.Win.Gd.DrawIcon(right.X, right.Y, tabHeight, gpu.NavigationChevronRight, fg)
+			if ctx.Win.LeftBtnClick(left) {
+				state.stripX -= stripRect.W / 2
+				ctx.Win.Invalidate()
+			} else if ctx.Win.LeftBtnClick(right) {
+				state.stripX += stripRect.W / 2
+				ctx.Win.Invalidate()
+			}
+		}
+
+		// Draw the active page
+		if len(tabs) > 0 && tabs[state.Active].Content != nil {
+			pageCtx := ctx
+			pageCtx.Rect = pageRect.Reduce(style.BorderWidth)
+			pageCtx.Baseline = 0
+			Scroller(state.scrollState(tabs[state.Active].Title), &style.Scroll, tabs[state.Active].Content)(pageCtx)
+		}
+
+		// Keys are handled after the page, so Tabs inside the page will get them first
+		for _, e := range ctx.Win.KeyEvents() {
+			if !e.Typed() || len(tabs) == 0 {
+				continue
+			}
+			n := len(tabs)
+			switch {
+			case e.Key == sys.KeyTab && e.Mods&sys.ModControl != 0 && (focused || ctx.Win.FocusInScope(state)):
+				if e.Mods&sys.ModShift != 0 {
+					clicked = (state.Active + n - 1) % n
+				} else {
+					clicked = (state.Active + 1) % n
+				}
+			case e.Key == sys.KeyRight && focused:
+				clicked = min(state.Active+1, n-1)
+			case e.Key == sys.KeyLeft && focused:
+				clicked = max(state.Active-1, 0)
+			default:
+				continue
+			}
+			e.Consume()
+		}
+
+		if closed >= 0 && state.OnClose != nil {
+			delete(state.scroll, tabs[closed].Title)
+			if closed < state.Active || closed == state.Active && closed == len(tabs)-1 {
+				state.Active = max(0, state.Active-1)
+			}
+			state.OnClose(closed)
+			ctx.Win.Invalidate()
+		} else if clicked >= 0 {
+			state.activate(ctx.Win, clicked)
+		}
+		return Dim{W: ctx.W, H: ctx.H}
+	}
+}
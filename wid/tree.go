@@ -0,0 +1,437 @@
+package wid
+
+import (
+	"github.com/jkvatne/jkvgui/f32"
+	"github.com/jkvatne/jkvgui/gpu"
+	"github.com/jkvatne/jkvgui/gpu/font"
+	"github.com/jkvatne/jkvgui/sys"
+	"github.com/jkvatne/jkvgui/theme"
+)
+
+// TreeNode is one node in a Tree.
+type TreeNode struct {
+	Text string
+	Icon *gpu.Icon
+	// Data is the user's data for the node, like a file path or a device id
+	Data any
+	// Children can be set when the node is made, or loaded by TreeState.Load when the node is first expanded.
+	Children []*TreeNode
+	// Leaf is true for nodes that can not have children, so no expand triangle is shown.
+	Leaf     bool
+	Expanded bool
+	parent   *TreeNode
+	loaded   bool
+	selected bool
+}
+
+// Parent returns the node's parent, or nil for the root nodes.
+// It is set when the node has been shown in the tree.
+func (n *TreeNode) Parent() *TreeNode {
+	return n.parent
+}
+
+// Selected is true if the node is selected
+func (n *TreeNode) Selected() bool {
+	return n.selected
+}
+
+// treeRow is a visible node, with its indentation level
+type treeRow struct {
+	node  *TreeNode
+	depth int
+}
+
+// TreeState is the state of a Tree, and must be kept between frames.
+type TreeState struct {
+	ScrollState
+	// Load is called the first time a node without children is expanded, and returns its children.
+	Load func(node *TreeNode) []*TreeNode
+	// MultiSelect allows selecting several nodes, with Ctrl-click, Shift-click and Shift+arrow keys.
+	MultiSelect bool
+	rows        []treeRow
+	dirty       bool
+	roots       []*TreeNode
+	current     *TreeNode
+	anchor      *TreeNode
+	selection   []*TreeNode
+	// scrollToCurrent is set when the cursor is moved by the keyboard
+	scrollToCurrent bool
+}
+
+// TreeStyle is the style of a Tree
+type TreeStyle struct {
+	ScrollStyle
+	Height       float32
+	FontNo       int
+	Color        theme.UIRole
+	SelectedRole theme.UIRole
+	GuideRole    theme.UIRole
+	BorderRole   theme.UIRole
+	BorderWidth  float32
+	CornerRadius float32
+	// Indent is the horizontal distance between the levels in the tree
+	Indent         float32
+	RowPadding     f32.Padding
+	OutsidePadding f32.Padding
+}
+
+var DefaultTree = &TreeStyle{
+	ScrollStyle:    DefaultScrollStyle,
+	Height:         0.5,
+	FontNo:         gpu.Normal12,
+	Color:          theme.OnSurface,
+	SelectedRole:   theme.SecondaryContainer,
+	GuideRole:      theme.Outline,
+	BorderRole:     theme.Outline,
+	BorderWidth:    1.0,
+	Indent:         16,
+	RowPadding:     f32.Padding{L: 2, T: 2, R: 2, B: 2},
+	OutsidePadding: f32.Padding{L: 5, T: 3, R: 4, B: 3},
+}
+
+// Current returns the node with the keyboard cursor, or nil.
+func (s *TreeState) Current() *TreeNode {
+	return s.current
+}
+
+// Selection returns the selected nodes, in the order they were selected.
+func (s *TreeState) Selection() []*TreeNode {
+	return s.selection
+}
+
+// Select makes the node the only selected node, and moves the cursor to it.
+func (s *TreeState) Select(n *TreeNode) {
+	s.ClearSelection()
+	s.setSelected(n, true)
+	s.current, s.anchor = n, n
+}
+
+// ClearSelection deselects all nodes
+func (s *TreeState) ClearSelection() {
+	for _, n := range s.selection {
+		n.selected = false
+	}
+	s.selection = nil
+}
+
+func (s *TreeState) setSelected(n *TreeNode, selected bool) {
+	if n == nil || n.selected == selected {
+		return
+	}
+	n.selected = selected
+	if selected {
+		s.selection = append(s.selection, n)
+		return
+	}
+	for i := range s.selection {
+		if s.selection[i] == n {
+			s.selection = append(s.selection[:i], s.selection[i+1:]...)
+			break
+		}
+	}
+}
+
+// hasChildren is true if the node has children, or can load them
+func (s *TreeState) hasChildren(n *TreeNode) bool {
+	return !n.Leaf && (len(n.Children) > 0 || !n.loaded && n.Children == nil && s.Load != nil)
+}
+
+// Expand shows the children of the node, loading them if needed.
+func (s *TreeState) Expand(n *TreeNode) {
+	if n.Children == nil && !n.loaded && !n.Leaf && s.Load != nil {
+		n.Children = s.Load(n)
+	}
+	n.loaded = true
+	n.Expanded = true
+	s.dirty = true
+}
+
+// Collapse hides the children of the node. If the cursor was on a child, it is moved to the node.
+func (s *TreeState) Collapse(n *TreeNode) {
+	n.Expanded = false
+	s.dirty = true
+	for p := s.current; p != nil; p = p.parent {
+		if p.parent == n {
+			s.current = n
+			break
+		}
+	}
+}
+
+// Toggle will expand a collapsed node, and collapse an expanded node.
+func (s *TreeState) Toggle(n *TreeNode) {
+	if n.Expanded {
+		s.Collapse(n)
+	} else if s.hasChildren(n) {
+		s.Expand(n)
+	}
+}
+
+// Refresh must be called when nodes are added to or removed from an expanded node.
+func (s *TreeState) Refresh() {
+	s.dirty = true
+}
+
+// update makes the list of visible rows when the tree has changed
+func (s *TreeState) update(roots []*TreeNode) {
+	if !s.dirty && len(roots) == len(s.roots) && (len(roots) == 0 || roots[0] == s.roots[0]) {
+		return
+	}
+	s.roots = roots
+	s.dirty = false
+	s.rows = s.rows[:0]
+	var add func(nodes []*TreeNode, parent *TreeNode, depth int)
+	add = func(nodes []*TreeNode, parent *TreeNode, depth int) {
+		for _, n := range nodes {
+			n.parent = parent
+			s.rows = append(s.rows, treeRow{node: n, depth: depth})
+			if n.Expanded {
+				add(n.Children, n, depth+1)
+			}
+		}
+	}
+	add(roots, nil, 0)
+}
+
+// index returns the row number of the node, or -1 if it is not visible
+func (s *TreeState) index(n *TreeNode) int {
+	for i := range s.rows {
+		if s.rows[i].node == n {
+			return i
+		}
+	}
+	return -1
+}
+
+// moveTo moves the cursor to the row, updating the selection as for a click with the given modifiers.
+func (s *TreeState) moveTo(i int, mods sys.ModifierKey) {
+	if len(s.rows) == 0 {
+		return
+	}
+	n := s.rows[max(0, min(i, len(s.rows)-1))].node
+	switch {
+	case s.MultiSelect && mods&sys.ModShift != 0:
+		s.current = n
+		s.selectRange()
+	case s.MultiSelect && mods&sys.ModControl != 0:
+		s.current = n
+	default:
+		s.Select(n)
+	}
+}
+
+// selectRange selects the rows from the anchor to the cursor
+func (s *TreeState) selectRange() {
+	a, b := s.index(s.anchor), s.index(s.current)
+	if a < 0 {
+		s.anchor, a = s.current, b
+	}
+	s.ClearSelection()
+	for i := min(a, b); i <= max(a, b); i++ {
+		s.setSelected(s.rows[i].node, true)
+	}
+}
+
+// click handles a mouse click on the node
+func (s *TreeState) click(n *TreeNode, mods sys.ModifierKey) {
+	switch {
+	case s.MultiSelect && mods&sys.ModShift != 0:
+		s.current = n
+		s.selectRange()
+	case s.MultiSelect && mods&sys.ModControl != 0:
+		s.setSelected(n, !n.selected)
+		s.current, s.anchor = n, n
+	default:
+		s.Select(n)
+	}
+}
+
+// treeKeys handles the keyboard when the tree has focus
+func treeKeys(ctx Ctx, s *TreeState, pageSize int) {
+	for _, e := range ctx.Win.KeyEvents() {
+		if !e.Typed() {
+			continue
+		}
+		s.update(s.roots)
+		i := s.index(s.current)
+		n := s.current
+		handled := true
+		switch {
+		case e.Key == sys.KeyDown:
+			s.moveTo(i+1, e.Mods)
+		case e.Key == sys.KeyUp:
+			s.moveTo(max(i-1, 0), e.Mods)
+		case e.Key == sys.KeyPageDown:
+			s.moveTo(i+pageSize, e.Mods)
+		case e.Key == sys.KeyPageUp:
+			s.moveTo(max(i-pageSize, 0), e.Mods)
+		case e.Key == sys.KeyHome:
+			s.moveTo(0, e.Mods)
+		case e.Key == sys.KeyEnd:
+			s.moveTo(len(s.rows)-1, e.Mods)
+		case n == nil:
+			handled = false
+		case e.Key == sys.KeyRight && !n.Expanded && s.hasChildren(n):
+			s.Expand(n)
+		case e.Key == sys.KeyRight && n.Expanded && len(n.Children) > 0:
+			s.moveTo(i+1, 0)
+		case e.Key == sys.KeyLeft && n.Expanded:
+			s.Collapse(n)
+		case e.Key == sys.KeyLeft && n.parent != nil:
+			s.Select(n.parent)
+		case e.Key == sys.KeyEnter || e.Key == sys.KeyKPEnter:
+			s.Toggle(n)
+		case e.Key == sys.KeySpace && s.MultiSelect:
+			s.setSelected(n, !n.selected)
+			s.anchor = n
+		default:
+			handled = false
+		}
+		if handled {
+			e.Consume()
+			s.scrollToCurrent = true
+		}
+	}
+}
+
+// scrollTree sets the scroll position from Ypos. All rows have the same height,
+// so the first visible row and the offset into it are found directly.
+func scrollTree(s *TreeState, rowHeight float32, h float32) {
+	s.Nmax, s.Nlast = len(s.rows), len(s.rows)
+	s.Ymax = float32(len(s.rows)) * rowHeight
+	s.Ylast = s.Ymax
+	if s.scrollToCurrent {
+		s.scrollToCurrent = false
+		if i := s.index(s.current); i >= 0 {
+			y := float32(i) * rowHeight
+			s.PendingScroll = 0
+			if y < s.Ypos {
+				s.Ypos = y
+			} else if y+rowHeight > s.Ypos+h {
+				s.Ypos = y + rowHeight - h
+			}
+		}
+	}
+	s.Ypos = max(0, min(s.Ypos, s.Ymax-h))
+	s.Npos = int(s.Ypos / rowHeight)
+	s.Dy = s.Ypos - float32(s.Npos)*rowHeight
+}
+
+// Tree shows hierarchical data, like a file system. Only the visible rows are drawn,
+// so it can be used for very large trees. Children are loaded by state.Load when a node is first expanded.
+// Nodes are expanded by clicking the triangle, by double-clicking or with the Right and Enter keys,
+// and selected by clicking or with the arrow keys.
+func Tree(state *TreeState, roots []*TreeNode, style *TreeStyle) Wid {
+	f32.ExitIf(state == nil, "Tree state must not be nil")
+	if style == nil {
+		style = DefaultTree
+	}
+	f := font.Get(style.FontNo)
+	rowHeight := f.Height + style.RowPadding.T + style.RowPadding.B
+
+	return func(ctx Ctx) Dim {
+		if ctx.Mode != RenderChildren {
+			if style.Height > 0.0 {
+				return Dim{W: ctx.W, H: style.Height}
+			}
+			return Dim{W: ctx.W, H: ctx.H}
+		}
+		ctx.Rect = ctx.Rect.Inset(style.OutsidePadding, style.BorderWidth)
+		if ctx.Win.LeftBtnPressed(ctx.Rect) {
+			ctx.Win.SetFocusedTag(state)
+		}
+		focused := ctx.Win.At(state)
+		bw := style.BorderWidth
+		if focused {
+			bw++
+		}
+		ctx.Win.Gd.RoundedRect(ctx.Rect, style.CornerRadius, bw, f32.Transparent, style.BorderRole.Bg())
+
+		state.update(roots)
+		if state.current == nil && len(state.rows) > 0 {
+			state.current = state.rows[0].node
+		}
+		if focused {
+			treeKeys(ctx, state, max(1, int(ctx.H/rowHeight)-1))
+			state.update(roots)
+		}
+		VertScollbarUserInput(ctx, &state.ScrollState, &style.ScrollStyle)
+		doScrolling(ctx, &state.ScrollState, func(n int) float32 { return rowHeight })
+		scrollTree(state, rowHeight, ctx.H)
+
+		ctx.Win.Gd.Clip(ctx.Rect)
+		fg := style.Color.Fg()
+		var clicked, toggled *TreeNode
+		y := ctx.Y - state.Dy
+		for i := state.Npos; i < len(state.rows) && y < ctx.Y+ctx.H; i++ {
+			row := state.rows[i]
+			n := row.node
+			rowRect := f32.Rect{X: ctx.X, Y: y, W: ctx.W - style.ScrollbarWidth, H: rowHeight}
+			x := ctx.X + float32(row.depth)*style.Indent
+			arrowRect := f32.Rect{X: x, Y: y, W: style.Indent, H: rowHeight}
+			if state.hasChildren(n) && ctx.Win.LeftBtnClick(arrowRect) {
+				toggled = n
+			} else if ctx.Win.LeftBtnDoubleClick(rowRect) {
+				toggled = n
+			} else if ctx.Win.LeftBtnClick(rowRect) {
+				clicked = n
+			}
+			if n.selected {
+				ctx.Win.Gd.SolidRect(rowRect, style.SelectedRole.Bg())
+			}
+			if focused && n == state.current {
+				ctx.Win.Gd.RoundedRect(rowRect, 0, 1, f32.Transparent, style.BorderRole.Bg())
+			}
+			// Indentation guides
+			for d := range row.depth {
+				gx := ctx.X + (float32(d)+0.5)*style.Indent
+				ctx.Win.Gd.VertLine(gx, y, y+rowHeight, 0.5, style.GuideRole.Bg())
+			}
+			if state.hasChildren(n) {
+				c := f32.Pos{X: x + style.Indent/2, Y: y + rowHeight/2}
+				d := min(style.Indent, rowHeight) / 4
+				var points []f32.Pos
+				if n.Expanded {
+					points = []f32.Pos{{X: c.X - d, Y: c.Y - d/2}, {X: c.X + d, Y: c.Y - d/2}, {X: c.X, Y: c.Y + d}}
+				} else {
+					points = []f32.Pos{{X: c.X - d/2, Y: c.Y - d}, {X: c.X + d, Y: c.Y}, {X: c.X - d/2, Y: c.Y + d}}
+				}
+				ctx.Win.Gd.Triangles(points, fg)
+			}
+			x += style.Indent
+			if n.Icon != nil {
+				ctx.Win.Gd.DrawIcon(x, y+style.RowPadding.T, f.Height, n.Icon, fg)
+				x += f.Height + style.RowPadding.L
+			}
+			f.DrawText(ctx.Win.Gd, x+style.RowPadding.L, y+style.RowPadding.T+f.Baseline, fg, rowRect.X+rowRect.W-x, gpu.LTR, n.Text)
+
+			as := sys.StateFocusable
+			if state.hasChildren(n) {
+				as |= sys.StateExpandable
+			}
+			if n.Expanded {
+				as |= sys.StateExpanded
+			}
+			if n.selected {
+				as |= sys.StateSelected
+			}
+			var tag any
+			if n == state.current {
+				tag = state
+			}
+			ctx.Win.AddAccessNode(sys.AccessNode{Role: sys.RoleTreeItem, Name: n.Text, State: as, Bounds: rowRect, Tag: tag})
+			y += rowHeight
+		}
+		gpu.NoClip()
+		if toggled != nil {
+			state.Toggle(toggled)
+			state.current = toggled
+			ctx.Win.Invalidate()
+		} else if clicked != nil {
+			state.click(clicked, ctx.Win.ModsDown())
+			ctx.Win.Invalidate()
+		}
+		DrawVertScrollbar(ctx, &state.ScrollState, &style.ScrollStyle)
+		return Dim{W: ctx.W, H: ctx.H}
+	}
+}
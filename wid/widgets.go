@@ -38,8 +38,7 @@ type Wid func(ctx Ctx) Dim
 // SetCursor will update the cursor type in the current window
 // This new cursor will be visible on next redraw
 func (ctx Ctx) SetCursor(id int) {
-	ctx.Win.SetCursor(sys.VResizeCursor)
-	ctx.Win.Cursor = id
+	ctx.Win.SetCursor(id)
 }
 
 // NewCtx returns a new context with the current window size
@@ -51,7 +50,7 @@ func NewCtx(win *sys.Window) Ctx {
 // Typically, the widget is a column or a scroller.
 func Show(w Wid) {
 	win := sys.GetCurrentWindow()
-	if win == nil || win.Window.ShouldClose() {
+	if win == nil || win.ShouldClose() {
 		return
 	}
 	ctx := NewCtx(win)